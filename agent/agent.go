@@ -0,0 +1,81 @@
+// Package agent provides export, import, sync, and diff of Dialogflow agents
+// in the layout of Dialogflow console's zip files.
+package agent
+
+import (
+	"context"
+	"sort"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Default language of agents.
+const DefaultLanguage = df.English
+
+// Agent with full intents and entities.
+type Agent struct {
+	Language df.LanguageTag         // language of user says and entity entries
+	Meta     map[string]interface{} // other fields of agent.json
+
+	Intents  []df.IntentObject
+	Entities []df.EntityObject
+}
+
+// Fetch all intents and entities of the agent with given client.
+func Fetch(ctx context.Context, client *df.Client, language df.LanguageTag) (agent *Agent, err error) {
+	return FetchWithOptions(ctx, client, language, df.BulkOptions{})
+}
+
+// Fetch all intents and entities of the agent with given client and bulk options.
+//
+// (fails if any of intents or entities could not be fetched)
+func FetchWithOptions(ctx context.Context, client *df.Client, language df.LanguageTag, options df.BulkOptions) (agent *Agent, err error) {
+	if len(language) <= 0 {
+		language = DefaultLanguage
+	}
+	agent = &Agent{
+		Language: language,
+		Meta:     map[string]interface{}{},
+	}
+
+	if agent.Intents, err = client.AllIntentObjectsContext(ctx, options); err != nil {
+		return nil, err
+	}
+	if agent.Entities, err = client.AllEntityObjectsContext(ctx, options); err != nil {
+		return nil, err
+	}
+
+	agent.sort()
+
+	return agent, nil
+}
+
+// Sort intents and entities by their names.
+func (a *Agent) sort() {
+	sort.SliceStable(a.Intents, func(i, j int) bool {
+		return a.Intents[i].Name < a.Intents[j].Name
+	})
+	sort.SliceStable(a.Entities, func(i, j int) bool {
+		return a.Entities[i].Name < a.Entities[j].Name
+	})
+}
+
+// Find an intent with given name.
+func (a *Agent) Intent(name string) (df.IntentObject, bool) {
+	for _, intent := range a.Intents {
+		if intent.Name == name {
+			return intent, true
+		}
+	}
+	return df.IntentObject{}, false
+}
+
+// Find an entity with given name.
+func (a *Agent) Entity(name string) (df.EntityObject, bool) {
+	for _, entity := range a.Entities {
+		if entity.Name == name {
+			return entity, true
+		}
+	}
+	return df.EntityObject{}, false
+}
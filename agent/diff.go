@@ -0,0 +1,331 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Type of changes.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// A change between two agent states.
+//
+// (From and To are empty for added or removed intents and entities)
+type Change struct {
+	Type ChangeType  `json:"type"`
+	Path string      `json:"path"` // eg. "intents/order/userSays", "entities/hero/entries/Roadhog/synonyms"
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Describe this change in a line.
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		if c.To == nil {
+			return fmt.Sprintf("+ %s", c.Path)
+		}
+		return fmt.Sprintf("+ %s: %s", c.Path, compactJson(c.To))
+	case ChangeRemoved:
+		if c.From == nil {
+			return fmt.Sprintf("- %s", c.Path)
+		}
+		return fmt.Sprintf("- %s: %s", c.Path, compactJson(c.From))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, compactJson(c.From), compactJson(c.To))
+}
+
+// Structural diff between two agent states.
+type Diff struct {
+	Changes []Change `json:"changes"`
+}
+
+// Check if there is no change.
+func (d *Diff) Empty() bool {
+	return len(d.Changes) <= 0
+}
+
+// Print changes in text.
+func (d *Diff) Print(w io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	for _, change := range d.Changes {
+		fmt.Fprintln(w, change.String())
+	}
+}
+
+// Write changes in json.
+func (d *Diff) WriteJson(w io.Writer) error {
+	data, err := marshal(d)
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	return err
+}
+
+// Compare two agent states. (eg. two exports, or an export and the live agent)
+func Compare(from, to *Agent) *Diff {
+	d := &Diff{Changes: []Change{}}
+
+	// intents
+	for _, intent := range from.Intents {
+		if _, exists := to.Intent(intent.Name); !exists {
+			d.add(ChangeRemoved, "intents/"+intent.Name, nil, nil)
+		}
+	}
+	for _, intent := range to.Intents {
+		if old, exists := from.Intent(intent.Name); exists {
+			d.compareIntents("intents/"+intent.Name, old, intent)
+		} else {
+			d.add(ChangeAdded, "intents/"+intent.Name, nil, nil)
+		}
+	}
+
+	// entities
+	for _, entity := range from.Entities {
+		if _, exists := to.Entity(entity.Name); !exists {
+			d.add(ChangeRemoved, "entities/"+entity.Name, nil, nil)
+		}
+	}
+	for _, entity := range to.Entities {
+		if old, exists := from.Entity(entity.Name); exists {
+			d.compareEntities("entities/"+entity.Name, old, entity)
+		} else {
+			d.add(ChangeAdded, "entities/"+entity.Name, nil, nil)
+		}
+	}
+
+	return d
+}
+
+// Compare an agent state with the live agent of given client.
+func CompareLive(ctx context.Context, client *df.Client, from *Agent) (*Diff, error) {
+	live, err := Fetch(ctx, client, from.Language)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(from, live), nil
+}
+
+// Append a change.
+func (d *Diff) add(typ ChangeType, path string, from, to interface{}) {
+	d.Changes = append(d.Changes, Change{Type: typ, Path: path, From: from, To: to})
+}
+
+// Append changes of two string lists, as sets.
+func (d *Diff) compareStrings(path string, from, to []string) {
+	fromSet, toSet := stringSet(from), stringSet(to)
+	for _, s := range from {
+		if !toSet[s] {
+			d.add(ChangeRemoved, path, s, nil)
+			toSet[s] = true // report duplicates only once
+		}
+	}
+	for _, s := range to {
+		if !fromSet[s] {
+			d.add(ChangeAdded, path, nil, s)
+			fromSet[s] = true
+		}
+	}
+}
+
+// Append a change of values when they differ.
+func (d *Diff) compareValues(path string, from, to interface{}) {
+	f, t := jsonValue(from), jsonValue(to)
+	if !reflect.DeepEqual(f, t) {
+		d.add(ChangeChanged, path, f, t)
+	}
+}
+
+// Compare two intents.
+func (d *Diff) compareIntents(path string, from, to df.IntentObject) {
+	// training phrases
+	d.compareStrings(path+"/userSays", phrases(from.UserSays), phrases(to.UserSays))
+
+	// context in
+	d.compareStrings(path+"/contexts", from.Contexts, to.Contexts)
+
+	// responses
+	for i := 0; i < len(from.Responses) || i < len(to.Responses); i++ {
+		p := fmt.Sprintf("%s/responses/%d", path, i)
+		if i >= len(to.Responses) {
+			d.add(ChangeRemoved, p, jsonValue(from.Responses[i]), nil)
+		} else if i >= len(from.Responses) {
+			d.add(ChangeAdded, p, nil, jsonValue(to.Responses[i]))
+		} else {
+			d.compareResponses(p, from.Responses[i], to.Responses[i])
+		}
+	}
+
+	// other fields
+	f, t := jsonValue(from), jsonValue(to)
+	fromObj, _ := f.(map[string]interface{})
+	toObj, _ := t.(map[string]interface{})
+	keys := map[string]interface{}{}
+	for k := range fromObj {
+		keys[k] = nil
+	}
+	for k := range toObj {
+		keys[k] = nil
+	}
+	for _, key := range sortedKeys(keys) {
+		switch key {
+		case "id", "status", "name", "userSays", "contexts", "responses":
+			continue
+		}
+		if !reflect.DeepEqual(fromObj[key], toObj[key]) {
+			d.add(ChangeChanged, path+"/"+key, fromObj[key], toObj[key])
+		}
+	}
+}
+
+// Compare two responses of an intent.
+func (d *Diff) compareResponses(path string, from, to df.IntentResponse) {
+	d.compareValues(path+"/action", from.Action, to.Action)
+	d.compareValues(path+"/resetContexts", from.ResetContexts, to.ResetContexts)
+
+	// context out
+	fromContexts, toContexts := map[string]df.IntentAffectedContext{}, map[string]df.IntentAffectedContext{}
+	for _, c := range from.AffectedContexts {
+		fromContexts[c.Name] = c
+	}
+	for _, c := range to.AffectedContexts {
+		toContexts[c.Name] = c
+	}
+	for _, c := range from.AffectedContexts {
+		if _, exists := toContexts[c.Name]; !exists {
+			d.add(ChangeRemoved, path+"/affectedContexts", jsonValue(c), nil)
+		}
+	}
+	for _, c := range to.AffectedContexts {
+		if old, exists := fromContexts[c.Name]; exists {
+			d.compareValues(path+"/affectedContexts/"+c.Name+"/lifespan", old.Lifespan, c.Lifespan)
+		} else {
+			d.add(ChangeAdded, path+"/affectedContexts", nil, jsonValue(c))
+		}
+	}
+
+	// parameters
+	fromParams, toParams := map[string]df.IntentResponseParameter{}, map[string]df.IntentResponseParameter{}
+	for _, p := range from.Parameters {
+		fromParams[p.Name] = p
+	}
+	for _, p := range to.Parameters {
+		toParams[p.Name] = p
+	}
+	for _, p := range from.Parameters {
+		if _, exists := toParams[p.Name]; !exists {
+			d.add(ChangeRemoved, path+"/parameters/"+p.Name, jsonValue(p), nil)
+		}
+	}
+	for _, p := range to.Parameters {
+		if old, exists := fromParams[p.Name]; exists {
+			d.compareValues(path+"/parameters/"+p.Name, old, p)
+		} else {
+			d.add(ChangeAdded, path+"/parameters/"+p.Name, nil, jsonValue(p))
+		}
+	}
+
+	// messages
+	for i := 0; i < len(from.Messages) || i < len(to.Messages); i++ {
+		p := fmt.Sprintf("%s/messages/%d", path, i)
+		if i >= len(to.Messages) {
+			d.add(ChangeRemoved, p, jsonValue(from.Messages[i]), nil)
+		} else if i >= len(from.Messages) {
+			d.add(ChangeAdded, p, nil, jsonValue(to.Messages[i]))
+		} else {
+			d.compareValues(p, from.Messages[i], to.Messages[i])
+		}
+	}
+
+	d.compareStrings(path+"/defaultResponsePlatforms", from.DefaultResponsePlatforms, to.DefaultResponsePlatforms)
+}
+
+// Compare two entities.
+func (d *Diff) compareEntities(path string, from, to df.EntityObject) {
+	d.compareValues(path+"/isEnum", from.IsEnum, to.IsEnum)
+	d.compareValues(path+"/automatedExpansion", from.AutomatedExpansion, to.AutomatedExpansion)
+
+	fromEntries := map[string]df.EntityEntryObject{}
+	for _, entry := range from.Entries {
+		fromEntries[entry.Value] = entry
+	}
+	toEntries := map[string]df.EntityEntryObject{}
+	for _, entry := range to.Entries {
+		toEntries[entry.Value] = entry
+	}
+
+	for _, entry := range from.Entries {
+		if _, exists := toEntries[entry.Value]; !exists {
+			d.add(ChangeRemoved, path+"/entries", jsonValue(entry), nil)
+		}
+	}
+	for _, entry := range to.Entries {
+		if old, exists := fromEntries[entry.Value]; exists {
+			d.compareStrings(path+"/entries/"+entry.Value+"/synonyms", old.Synonyms, entry.Synonyms)
+		} else {
+			d.add(ChangeAdded, path+"/entries", nil, jsonValue(entry))
+		}
+	}
+}
+
+// Get training phrases of user says.
+//
+// (annotated parts are written as '[text](meta:alias)', so that changes of annotations are also compared)
+func phrases(userSays []df.UserSays) []string {
+	phrases := []string{}
+	for _, says := range userSays {
+		texts := []string{}
+		for _, data := range says.Data {
+			if len(data.Meta) > 0 || len(data.Alias) > 0 {
+				texts = append(texts, fmt.Sprintf("[%s](%s:%s)", data.Text, data.Meta, data.Alias))
+			} else {
+				texts = append(texts, data.Text)
+			}
+		}
+		phrases = append(phrases, strings.Join(texts, ""))
+	}
+	return phrases
+}
+
+// Get a set of strings.
+func stringSet(strs []string) map[string]bool {
+	set := map[string]bool{}
+	for _, s := range strs {
+		set[s] = true
+	}
+	return set
+}
+
+// Convert a value into a generic json value.
+func jsonValue(v interface{}) (value interface{}) {
+	if data, err := json.Marshal(v); err == nil {
+		if err = json.Unmarshal(data, &value); err == nil {
+			return value
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Marshal a value into compact json for printing.
+func compactJson(v interface{}) string {
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
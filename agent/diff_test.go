@@ -0,0 +1,152 @@
+package agent_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/agent"
+)
+
+// Get an agent state for diff tests.
+func diffAgent(phrases []df.UserSays, lifespan int, params []df.IntentResponseParameter, synonyms []string) *agent.Agent {
+	return &agent.Agent{
+		Language: df.English,
+		Intents: []df.IntentObject{{
+			Name:     "order",
+			UserSays: phrases,
+			Responses: []df.IntentResponse{{
+				Action:           "order",
+				AffectedContexts: []df.IntentAffectedContext{{Name: "ordering", Lifespan: lifespan}},
+				Parameters:       params,
+			}},
+		}},
+		Entities: []df.EntityObject{{
+			Name:    "food",
+			Entries: []df.EntityEntryObject{{Value: "pizza", Synonyms: synonyms}},
+		}},
+	}
+}
+
+// Get a phrase with an annotated part.
+func annotated(prefix, text, meta, alias string) df.UserSays {
+	return df.UserSays{Data: []df.UserSaysData{{Text: prefix}, {Text: text, Meta: meta, Alias: alias}}}
+}
+
+// Get a phrase without annotations.
+func plain(text string) df.UserSays {
+	return df.UserSays{Data: []df.UserSaysData{{Text: text}}}
+}
+
+func TestCompare(t *testing.T) {
+	foodParam := df.IntentResponseParameter{Name: "food", DataType: "@food", Value: "$food"}
+	requiredFoodParam := foodParam
+	requiredFoodParam.Required = true
+	sizeParam := df.IntentResponseParameter{Name: "size", DataType: "@sys.number", Value: "$size"}
+
+	from := diffAgent(
+		[]df.UserSays{plain("hi"), annotated("I want a ", "pizza", "@food", "food"), plain("one more")},
+		2,
+		[]df.IntentResponseParameter{foodParam, sizeParam},
+		[]string{"pizza", "pie"},
+	)
+	to := diffAgent(
+		[]df.UserSays{plain("hi"), annotated("I want a ", "pizza", "@sys.any", "food"), plain("a new one")},
+		5,
+		[]df.IntentResponseParameter{requiredFoodParam},
+		[]string{"pizza", "za"},
+	)
+
+	if d := agent.Compare(from, from); !d.Empty() {
+		t.Errorf("expected no changes for the same agent, got %v", d.Changes)
+	}
+
+	d := agent.Compare(from, to)
+	expected := []agent.Change{
+		{Type: agent.ChangeRemoved, Path: "intents/order/userSays", From: "I want a [pizza](@food:food)"},
+		{Type: agent.ChangeRemoved, Path: "intents/order/userSays", From: "one more"},
+		{Type: agent.ChangeAdded, Path: "intents/order/userSays", To: "I want a [pizza](@sys.any:food)"},
+		{Type: agent.ChangeAdded, Path: "intents/order/userSays", To: "a new one"},
+		{Type: agent.ChangeChanged, Path: "intents/order/responses/0/affectedContexts/ordering/lifespan", From: 2.0, To: 5.0},
+		{Type: agent.ChangeRemoved, Path: "intents/order/responses/0/parameters/size"},
+		{Type: agent.ChangeChanged, Path: "intents/order/responses/0/parameters/food"},
+		{Type: agent.ChangeRemoved, Path: "entities/food/entries/pizza/synonyms", From: "pie"},
+		{Type: agent.ChangeAdded, Path: "entities/food/entries/pizza/synonyms", To: "za"},
+	}
+	if len(d.Changes) != len(expected) {
+		var buf bytes.Buffer
+		d.Print(&buf)
+		t.Fatalf("expected %d changes, got %d:\n%s", len(expected), len(d.Changes), buf.String())
+	}
+	for i, change := range d.Changes {
+		e := expected[i]
+		if change.Type != e.Type || change.Path != e.Path {
+			t.Errorf("change #%d: expected %s %s, got %s", i, e.Type, e.Path, change)
+			continue
+		}
+		if strings.Contains(e.Path, "/parameters/") { // whole parameter objects
+			if (e.Type == agent.ChangeRemoved) != (change.From != nil && change.To == nil) {
+				t.Errorf("change #%d: unexpected values: %s", i, change)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(change.From, e.From) || !reflect.DeepEqual(change.To, e.To) {
+			t.Errorf("change #%d: expected %s, got %s", i, e, change)
+		}
+	}
+	if param, ok := d.Changes[6].To.(map[string]interface{}); !ok || param["required"] != true {
+		t.Errorf("expected changed parameter to be required, got %s", d.Changes[6])
+	}
+}
+
+func TestCompareAddedAndRemoved(t *testing.T) {
+	from := &agent.Agent{Intents: []df.IntentObject{{Name: "old"}}, Entities: []df.EntityObject{{Name: "stale"}}}
+	to := &agent.Agent{Intents: []df.IntentObject{{Name: "new"}}, Entities: []df.EntityObject{{Name: "fresh"}}}
+
+	var buf bytes.Buffer
+	agent.Compare(from, to).Print(&buf)
+	if expected := "- intents/old\n+ intents/new\n- entities/stale\n+ entities/fresh\n"; buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	agent.Compare(from, from).Print(&buf)
+	if buf.String() != "No changes.\n" {
+		t.Errorf("unexpected output for no changes: %s", buf.String())
+	}
+}
+
+func TestDiffWriteJson(t *testing.T) {
+	from := diffAgent([]df.UserSays{plain("hi")}, 2, nil, []string{"pizza"})
+	to := diffAgent([]df.UserSays{plain("hello")}, 3, nil, []string{"pizza"})
+
+	var buf bytes.Buffer
+	if err := agent.Compare(from, to).WriteJson(&buf); err != nil {
+		t.Fatalf("failed to write json: %s", err)
+	}
+
+	var written struct {
+		Changes []map[string]interface{} `json:"changes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &written); err != nil {
+		t.Fatalf("failed to decode written json: %s\n%s", err, buf.String())
+	}
+	expected := []map[string]interface{}{
+		{"type": "removed", "path": "intents/order/userSays", "from": "hi"},
+		{"type": "added", "path": "intents/order/userSays", "to": "hello"},
+		{"type": "changed", "path": "intents/order/responses/0/affectedContexts/ordering/lifespan", "from": 2.0, "to": 3.0},
+	}
+	if !reflect.DeepEqual(written.Changes, expected) {
+		t.Errorf("expected %v, got %v", expected, written.Changes)
+	}
+
+	// empty diff is written as an empty array
+	buf.Reset()
+	agent.Compare(from, from).WriteJson(&buf)
+	if !strings.Contains(buf.String(), `"changes": []`) {
+		t.Errorf("expected empty changes, got %s", buf.String())
+	}
+}
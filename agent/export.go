@@ -0,0 +1,237 @@
+package agent
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Directory names in the exported layout.
+const (
+	IntentsDir  = "intents"
+	EntitiesDir = "entities"
+)
+
+// Fixed modification time of zip entries, for deterministic outputs.
+var zipModified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Replacer for characters which cannot be used in file names.
+var fileNameReplacer = strings.NewReplacer(
+	"/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_",
+)
+
+// Get a file name for given intent or entity name.
+func fileName(name string) string {
+	return fileNameReplacer.Replace(name)
+}
+
+// Convert a value to a generic json object, whose keys are marshalled in sorted order.
+func toJsonObject(v interface{}) (obj map[string]interface{}, err error) {
+	var data []byte
+	if data, err = json.Marshal(v); err == nil {
+		err = json.Unmarshal(data, &obj)
+	}
+	return obj, err
+}
+
+// Marshal a value into indented json.
+func marshal(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Generate files of the exported layout. (key: slash-separated path)
+//
+// Returns an error when names of intents or entities map to the same file. (eg. 'a/b' and 'a_b')
+func (a *Agent) Files() (files map[string][]byte, err error) {
+	files = map[string][]byte{}
+
+	// put a file, checking collisions
+	owners := map[string]string{} // key: path, value: owner's description
+	put := func(p, owner string, v interface{}) (err error) {
+		if existing, exists := owners[p]; exists {
+			return fmt.Errorf("file name of %s collides with %s: %s", owner, existing, p)
+		}
+		owners[p] = owner
+
+		files[p], err = marshal(v)
+		return err
+	}
+
+	language := a.Language
+	if len(language) <= 0 {
+		language = DefaultLanguage
+	}
+
+	// agent.json, package.json
+	meta := map[string]interface{}{}
+	for k, v := range a.Meta {
+		meta[k] = v
+	}
+	meta["language"] = language
+	if files["agent.json"], err = marshal(meta); err != nil {
+		return nil, err
+	}
+	if files["package.json"], err = marshal(map[string]interface{}{"version": "1.0.0"}); err != nil {
+		return nil, err
+	}
+
+	// intents
+	for _, intent := range a.Intents {
+		var obj map[string]interface{}
+		if obj, err = toJsonObject(intent); err != nil {
+			return nil, err
+		}
+		delete(obj, "status")
+		delete(obj, "userSays")
+
+		name, owner := fileName(intent.Name), fmt.Sprintf("intent '%s'", intent.Name)
+		if err = put(fmt.Sprintf("%s/%s.json", IntentsDir, name), owner, obj); err != nil {
+			return nil, err
+		}
+
+		if len(intent.UserSays) > 0 {
+			if err = put(fmt.Sprintf("%s/%s_usersays_%s.json", IntentsDir, name, language), owner, intent.UserSays); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// entities
+	for _, entity := range a.Entities {
+		var obj map[string]interface{}
+		if obj, err = toJsonObject(entity); err != nil {
+			return nil, err
+		}
+		delete(obj, "status")
+		delete(obj, "entries")
+
+		name, owner := fileName(entity.Name), fmt.Sprintf("entity '%s'", entity.Name)
+		if err = put(fmt.Sprintf("%s/%s.json", EntitiesDir, name), owner, obj); err != nil {
+			return nil, err
+		}
+
+		entries := entity.Entries
+		if entries == nil {
+			entries = []df.EntityEntryObject{}
+		}
+		if err = put(fmt.Sprintf("%s/%s_entries_%s.json", EntitiesDir, name, language), owner, entries); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// Get sorted paths of files.
+func sortedPaths(files map[string][]byte) []string {
+	paths := []string{}
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write the agent into given directory.
+//
+// (existing .json files in intents/ and entities/ are removed first, so that renamed or deleted ones do not remain)
+func (a *Agent) WriteDir(dir string) (err error) {
+	var files map[string][]byte
+	if files, err = a.Files(); err != nil {
+		return err
+	}
+
+	for _, sub := range []string{IntentsDir, EntitiesDir} {
+		var olds []string
+		if olds, err = filepath.Glob(filepath.Join(dir, sub, "*.json")); err != nil {
+			return err
+		}
+		for _, old := range olds {
+			if err = os.Remove(old); err != nil {
+				return err
+			}
+		}
+		if err = os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range sortedPaths(files) {
+		if err = ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(path)), files[path], 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Write the agent as a zip file into given writer.
+func (a *Agent) WriteZip(w io.Writer) (err error) {
+	var files map[string][]byte
+	if files, err = a.Files(); err != nil {
+		return err
+	}
+
+	writer := zip.NewWriter(w)
+	for _, path := range sortedPaths(files) {
+		var fw io.Writer
+		if fw, err = writer.CreateHeader(&zip.FileHeader{
+			Name:     path,
+			Method:   zip.Deflate,
+			Modified: zipModified,
+		}); err != nil {
+			return err
+		}
+		if _, err = fw.Write(files[path]); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// Fetch the agent with given client and export it into given directory.
+func ExportDir(ctx context.Context, client *df.Client, language df.LanguageTag, dir string) error {
+	agent, err := Fetch(ctx, client, language)
+	if err != nil {
+		return err
+	}
+	return agent.WriteDir(dir)
+}
+
+// Fetch the agent with given client and export it as a zip file into given writer.
+func ExportZip(ctx context.Context, client *df.Client, language df.LanguageTag, w io.Writer) error {
+	agent, err := Fetch(ctx, client, language)
+	if err != nil {
+		return err
+	}
+	return agent.WriteZip(w)
+}
+
+// Get sorted keys of a json object.
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := []string{}
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
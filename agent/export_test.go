@@ -0,0 +1,54 @@
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/agent"
+)
+
+func TestFilesRoundTrip(t *testing.T) {
+	a := &agent.Agent{
+		Language: df.English,
+		Intents: []df.IntentObject{
+			{Name: "order", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "pizza"}}}}},
+		},
+		Entities: []df.EntityObject{
+			{Name: "hero", Entries: []df.EntityEntryObject{{Value: "Roadhog", Synonyms: []string{"pig"}}}},
+		},
+	}
+
+	files, err := a.Files()
+	if err != nil {
+		t.Fatalf("failed to generate files: %s", err)
+	}
+	for _, p := range []string{"agent.json", "package.json", "intents/order.json", "intents/order_usersays_en.json", "entities/hero.json", "entities/hero_entries_en.json"} {
+		if _, exists := files[p]; !exists {
+			t.Errorf("expected file %s", p)
+		}
+	}
+
+	read, err := agent.ReadFiles(files)
+	if err != nil {
+		t.Fatalf("failed to read files: %s", err)
+	}
+	if d := agent.Compare(a, read); !d.Empty() {
+		t.Errorf("expected no changes after round trip, got %v", d.Changes)
+	}
+}
+
+func TestFilesNameCollision(t *testing.T) {
+	for _, a := range []*agent.Agent{
+		{Intents: []df.IntentObject{{Name: "a/b"}, {Name: "a_b"}}},
+		{Entities: []df.EntityObject{{Name: "a:b"}, {Name: "a?b"}}},
+		{Intents: []df.IntentObject{
+			{Name: "order", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "pizza"}}}}},
+			{Name: "order_usersays_en"},
+		}},
+	} {
+		if _, err := a.Files(); err == nil || !strings.Contains(err.Error(), "collides") {
+			t.Errorf("expected a collision error, got %v", err)
+		}
+	}
+}
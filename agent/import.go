@@ -0,0 +1,295 @@
+package agent
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Patterns of file names (without extensions) of user says and entries. (eg. 'order_usersays_en', 'hero_entries_pt-br')
+var (
+	userSaysFilePattern = regexp.MustCompile(`(?i)_usersays_[a-z]{2,3}(-[a-z0-9]+)*$`)
+	entriesFilePattern  = regexp.MustCompile(`(?i)_entries_[a-z]{2,3}(-[a-z0-9]+)*$`)
+)
+
+// Check if given json data is an array. (user says and entries files are arrays, while intents and entities are objects)
+func isJsonArray(data []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(data)), "[")
+}
+
+// Read an agent from files of the exported layout. (key: slash-separated path)
+//
+// (user says and entries of other languages are ignored)
+func ReadFiles(files map[string][]byte) (agent *Agent, err error) {
+	agent = &Agent{
+		Language: DefaultLanguage,
+		Meta:     map[string]interface{}{},
+		Intents:  []df.IntentObject{},
+		Entities: []df.EntityObject{},
+	}
+
+	// strip the common root directory (eg. zip files which contain 'agent-name/agent.json')
+	root := ""
+	for p := range files {
+		if path.Base(p) == "agent.json" {
+			root = path.Dir(p)
+			break
+		}
+	}
+	if root != "" && root != "." {
+		stripped := map[string][]byte{}
+		for p, data := range files {
+			if strings.HasPrefix(p, root+"/") {
+				stripped[strings.TrimPrefix(p, root+"/")] = data
+			}
+		}
+		files = stripped
+	}
+
+	if data, exists := files["agent.json"]; exists {
+		if err = json.Unmarshal(data, &agent.Meta); err != nil {
+			return nil, fmt.Errorf("failed to read agent.json: %s", err)
+		}
+		if language, ok := agent.Meta["language"].(string); ok && len(language) > 0 {
+			agent.Language = df.LanguageTag(language)
+		}
+		delete(agent.Meta, "language")
+	}
+
+	for _, p := range sortedPaths(files) {
+		dir, name := path.Split(p)
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		base := strings.TrimSuffix(name, ".json")
+
+		switch strings.TrimSuffix(dir, "/") {
+		case IntentsDir:
+			if userSaysFilePattern.MatchString(base) && isJsonArray(files[p]) {
+				continue
+			}
+
+			var intent df.IntentObject
+			if err = json.Unmarshal(files[p], &intent); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", p, err)
+			}
+			if data, exists := files[fmt.Sprintf("%s/%s_usersays_%s.json", IntentsDir, base, agent.Language)]; exists {
+				if err = json.Unmarshal(data, &intent.UserSays); err != nil {
+					return nil, fmt.Errorf("failed to read user says of %s: %s", p, err)
+				}
+			}
+			agent.Intents = append(agent.Intents, intent)
+		case EntitiesDir:
+			if entriesFilePattern.MatchString(base) && isJsonArray(files[p]) {
+				continue
+			}
+
+			var entity df.EntityObject
+			if err = json.Unmarshal(files[p], &entity); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %s", p, err)
+			}
+			if data, exists := files[fmt.Sprintf("%s/%s_entries_%s.json", EntitiesDir, base, agent.Language)]; exists {
+				if err = json.Unmarshal(data, &entity.Entries); err != nil {
+					return nil, fmt.Errorf("failed to read entries of %s: %s", p, err)
+				}
+			}
+			agent.Entities = append(agent.Entities, entity)
+		}
+	}
+
+	agent.sort()
+
+	return agent, nil
+}
+
+// Read an agent from given directory.
+func ReadDir(dir string) (*Agent, error) {
+	files := map[string][]byte{}
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		var rel string
+		if rel, err = filepath.Rel(dir, p); err == nil {
+			files[filepath.ToSlash(rel)], err = ioutil.ReadFile(p)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadFiles(files)
+}
+
+// Read an agent from a zip file.
+func ReadZip(r io.ReaderAt, size int64) (*Agent, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		var rc io.ReadCloser
+		if rc, err = f.Open(); err != nil {
+			return nil, err
+		}
+		files[f.Name], err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ReadFiles(files)
+}
+
+// Mode of import.
+type ImportMode int
+
+const (
+	// Create missing intents and entities, and update existing ones. (entries are added to existing entities)
+	ImportMerge ImportMode = iota
+
+	// Same as ImportMerge, but entries of existing entities are replaced,
+	// and intents and entities which are not in the imported agent are deleted.
+	ImportReplace
+)
+
+// Result of import.
+type ImportResult struct {
+	IntentIds map[string]string // key: intent name, value: intent id
+	EntityIds map[string]string // key: entity name, value: entity id
+
+	Created []string // eg. "intent:name", "entity:name"
+	Updated []string
+	Deleted []string
+}
+
+// Import given agent with given client.
+//
+// Entities are created or updated before intents which reference them,
+// and ids of intents and entities are looked up by their names. (ids in the imported agent are ignored)
+func Import(ctx context.Context, client *df.Client, agent *Agent, mode ImportMode) (result ImportResult, err error) {
+	result = ImportResult{
+		IntentIds: map[string]string{},
+		EntityIds: map[string]string{},
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+	}
+
+	// existing entities and intents
+	var entities df.Entities
+	if entities, err = client.AllEntitiesContext(ctx); err != nil {
+		return result, err
+	}
+	existingEntities := map[string]string{}
+	for _, entity := range entities.Entities {
+		existingEntities[entity.Name] = entity.Id
+	}
+	var intents []df.Intent
+	if intents, err = client.AllIntentsContext(ctx); err != nil {
+		return result, err
+	}
+	existingIntents := map[string]string{}
+	for _, intent := range intents {
+		existingIntents[intent.Name] = intent.Id
+	}
+
+	// entities first
+	for _, entity := range agent.Entities {
+		entity.ApiResponse = df.ApiResponse{}
+
+		var res df.ApiResponse
+		if id, exists := existingEntities[entity.Name]; exists {
+			if mode == ImportReplace {
+				res, err = client.UpdateEntityContext(ctx, id, entity)
+			} else {
+				res, err = client.AddEntityEntriesContext(ctx, id, entity.Entries)
+			}
+			if err != nil {
+				return result, fmt.Errorf("failed to update entity '%s': %w", entity.Name, err)
+			}
+			result.EntityIds[entity.Name] = id
+			result.Updated = append(result.Updated, "entity:"+entity.Name)
+		} else {
+			attributed := entity
+
+			entity.IsEnum, entity.AutomatedExpansion = false, false // should not be filled on creation
+			if res, err = client.CreateEntityContext(ctx, entity); err != nil {
+				return result, fmt.Errorf("failed to create entity '%s': %w", entity.Name, err)
+			}
+
+			// attributes cannot be set on creation, so update them after it
+			if attributed.IsEnum || attributed.AutomatedExpansion {
+				if _, err = client.UpdateEntityContext(ctx, res.Id, attributed); err != nil {
+					return result, fmt.Errorf("failed to update attributes of entity '%s': %w", entity.Name, err)
+				}
+			}
+			result.EntityIds[entity.Name] = res.Id
+			result.Created = append(result.Created, "entity:"+entity.Name)
+		}
+	}
+
+	// then intents
+	for _, intent := range agent.Intents {
+		intent.ApiResponse = df.ApiResponse{}
+
+		var res df.ApiResponse
+		if id, exists := existingIntents[intent.Name]; exists {
+			if _, err = client.UpdateIntentContext(ctx, id, intent); err != nil {
+				return result, fmt.Errorf("failed to update intent '%s': %w", intent.Name, err)
+			}
+			result.IntentIds[intent.Name] = id
+			result.Updated = append(result.Updated, "intent:"+intent.Name)
+		} else {
+			if res, err = client.CreateIntentContext(ctx, intent); err != nil {
+				return result, fmt.Errorf("failed to create intent '%s': %w", intent.Name, err)
+			}
+			result.IntentIds[intent.Name] = res.Id
+			result.Created = append(result.Created, "intent:"+intent.Name)
+		}
+	}
+
+	if mode != ImportReplace {
+		return result, nil
+	}
+
+	// delete intents before entities which they may reference
+	for _, intent := range intents {
+		if _, exists := agent.Intent(intent.Name); !exists {
+			if _, err = client.DeleteIntentContext(ctx, intent.Id); err != nil {
+				return result, fmt.Errorf("failed to delete intent '%s': %w", intent.Name, err)
+			}
+			result.Deleted = append(result.Deleted, "intent:"+intent.Name)
+		}
+	}
+	for _, entity := range entities.Entities {
+		if _, exists := agent.Entity(entity.Name); !exists {
+			if _, err = client.DeleteEntityContext(ctx, entity.Id); err != nil {
+				return result, fmt.Errorf("failed to delete entity '%s': %w", entity.Name, err)
+			}
+			result.Deleted = append(result.Deleted, "entity:"+entity.Name)
+		}
+	}
+
+	return result, nil
+}
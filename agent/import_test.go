@@ -0,0 +1,175 @@
+package agent_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/agent"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestReadFilesKeepsNamesWithSuffixWords(t *testing.T) {
+	a := &agent.Agent{
+		Language: df.English,
+		Intents: []df.IntentObject{
+			{Name: "count_usersays_total", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "how many"}}}}},
+			{Name: "list_usersays_all"},
+		},
+		Entities: []df.EntityObject{
+			{Name: "my_entries_list", Entries: []df.EntityEntryObject{{Value: "a", Synonyms: []string{"a"}}}},
+		},
+	}
+
+	files, err := a.Files()
+	if err != nil {
+		t.Fatalf("failed to generate files: %s", err)
+	}
+
+	// user says and entries of other languages are ignored
+	files["intents/count_usersays_total_usersays_pt-BR.json"] = []byte(`[]`)
+	files["entities/my_entries_list_entries_ko.json"] = []byte(`[]`)
+
+	read, err := agent.ReadFiles(files)
+	if err != nil {
+		t.Fatalf("failed to read files: %s", err)
+	}
+	if intent, exists := read.Intent("count_usersays_total"); !exists || len(intent.UserSays) != 1 {
+		t.Errorf("expected intent 'count_usersays_total' with its user says, got %+v", read.Intents)
+	}
+	if entity, exists := read.Entity("my_entries_list"); !exists || len(entity.Entries) != 1 {
+		t.Errorf("expected entity 'my_entries_list' with its entries, got %+v", read.Entities)
+	}
+	if _, exists := read.Intent("list_usersays_all"); !exists {
+		t.Errorf("expected intent 'list_usersays_all', got %+v", read.Intents)
+	}
+	if len(read.Intents) != 2 || len(read.Entities) != 1 {
+		t.Errorf("expected 2 intents and 1 entity, got %d and %d", len(read.Intents), len(read.Entities))
+	}
+}
+
+// Get a fake server with existing intents and entities for import tests.
+func importServer() *dialogflowtest.Server {
+	s := dialogflowtest.NewServer()
+
+	s.AddIntent(df.IntentObject{Name: "greet", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "hi"}}}}})
+	s.AddIntent(df.IntentObject{Name: "old"})
+	s.AddEntity(df.EntityObject{Name: "hero", Entries: []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog"}},
+		{Value: "Genji", Synonyms: []string{"Genji"}},
+	}})
+	s.AddEntity(df.EntityObject{Name: "stale"})
+
+	return s
+}
+
+// Agent to be imported into the server of importServer().
+func importedAgent() *agent.Agent {
+	return &agent.Agent{
+		Language: df.English,
+		Intents: []df.IntentObject{
+			{Name: "greet", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "hello"}}}}},
+			{Name: "order", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "I want a pizza"}}}}},
+		},
+		Entities: []df.EntityObject{
+			{Name: "hero", Entries: []df.EntityEntryObject{
+				{Value: "Roadhog", Synonyms: []string{"Roadhog", "pig"}},
+				{Value: "Mercy", Synonyms: []string{"Mercy"}},
+			}},
+			{Name: "size", IsEnum: true, AutomatedExpansion: true, Entries: []df.EntityEntryObject{
+				{Value: "large", Synonyms: []string{"large"}},
+			}},
+		},
+	}
+}
+
+// Get names of intents and entities on the server.
+func serverNames(s *dialogflowtest.Server) (intents, entities []string) {
+	for _, intent := range s.Intents() {
+		intents = append(intents, intent.Name)
+	}
+	for _, entity := range s.Entities() {
+		entities = append(entities, entity.Name)
+	}
+	sort.Strings(intents)
+	sort.Strings(entities)
+	return intents, entities
+}
+
+// Get values of entries of an entity on the server.
+func entryValues(s *dialogflowtest.Server, name string) (values []string) {
+	for _, entity := range s.Entities() {
+		if entity.Name == name {
+			for _, entry := range entity.Entries {
+				values = append(values, entry.Value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func TestImport(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		mode     agent.ImportMode
+		intents  []string
+		entities []string
+		heroes   []string
+		deleted  []string
+	}{
+		{
+			name:     "merge",
+			mode:     agent.ImportMerge,
+			intents:  []string{"greet", "old", "order"},
+			entities: []string{"hero", "size", "stale"},
+			heroes:   []string{"Genji", "Mercy", "Roadhog"},
+			deleted:  []string{},
+		},
+		{
+			name:     "replace",
+			mode:     agent.ImportReplace,
+			intents:  []string{"greet", "order"},
+			entities: []string{"hero", "size"},
+			heroes:   []string{"Mercy", "Roadhog"},
+			deleted:  []string{"intent:old", "entity:stale"},
+		},
+	} {
+		s := importServer()
+
+		result, err := agent.Import(context.Background(), s.NewClient("token"), importedAgent(), test.mode)
+		if err != nil {
+			t.Errorf("%s: failed to import: %s", test.name, err)
+			s.Close()
+			continue
+		}
+
+		if !reflect.DeepEqual(result.Created, []string{"entity:size", "intent:order"}) ||
+			!reflect.DeepEqual(result.Updated, []string{"entity:hero", "intent:greet"}) ||
+			!reflect.DeepEqual(result.Deleted, test.deleted) {
+			t.Errorf("%s: unexpected result: %+v", test.name, result)
+		}
+		if len(result.IntentIds) != 2 || len(result.EntityIds) != 2 {
+			t.Errorf("%s: unexpected ids: %+v / %+v", test.name, result.IntentIds, result.EntityIds)
+		}
+
+		intents, entities := serverNames(s)
+		if !reflect.DeepEqual(intents, test.intents) || !reflect.DeepEqual(entities, test.entities) {
+			t.Errorf("%s: unexpected intents and entities: %v / %v", test.name, intents, entities)
+		}
+		if heroes := entryValues(s, "hero"); !reflect.DeepEqual(heroes, test.heroes) {
+			t.Errorf("%s: expected entries %v, got %v", test.name, test.heroes, heroes)
+		}
+
+		// attributes which cannot be set on creation
+		for _, entity := range s.Entities() {
+			if entity.Name == "size" && (!entity.IsEnum || !entity.AutomatedExpansion) {
+				t.Errorf("%s: expected attributes of created entity, got %+v", test.name, entity)
+			}
+		}
+
+		s.Close()
+	}
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Spec file of desired agent states, in YAML or JSON.
+//
+// (fields of intents and entities are the same as the api's json fields)
+//
+//	language: en
+//	entities:
+//	  - name: hero
+//	    entries:
+//	      - value: Roadhog
+//	        synonyms: [Roadhog, pig]
+//	intents:
+//	  - name: order
+//	    userSays:
+//	      - data:
+//	          - text: I want a pizza
+type Spec struct {
+	Language df.LanguageTag    `json:"language,omitempty"`
+	Intents  []df.IntentObject `json:"intents,omitempty"`
+	Entities []df.EntityObject `json:"entities,omitempty"`
+}
+
+// Load a desired agent from spec files or directories. (*.json, *.yaml, *.yml)
+//
+// Intents and entities of all files are merged, and duplicated names are not allowed.
+func LoadSpec(paths ...string) (agent *Agent, err error) {
+	files := []string{}
+	for _, p := range paths {
+		var info os.FileInfo
+		if info, err = os.Stat(p); err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		if err = filepath.Walk(p, func(p string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && isSpecFile(p) {
+				files = append(files, p)
+			}
+			return err
+		}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	agent = &Agent{
+		Meta:     map[string]interface{}{},
+		Intents:  []df.IntentObject{},
+		Entities: []df.EntityObject{},
+	}
+	for _, file := range files {
+		var spec Spec
+		if spec, err = readSpec(file); err != nil {
+			return nil, fmt.Errorf("failed to read spec '%s': %s", file, err)
+		}
+
+		if len(spec.Language) > 0 {
+			if len(agent.Language) > 0 && agent.Language != spec.Language {
+				return nil, fmt.Errorf("language of spec '%s' (%s) differs from others (%s)", file, spec.Language, agent.Language)
+			}
+			agent.Language = spec.Language
+		}
+		for _, intent := range spec.Intents {
+			if _, exists := agent.Intent(intent.Name); exists {
+				return nil, fmt.Errorf("duplicated intent '%s' in spec '%s'", intent.Name, file)
+			}
+			agent.Intents = append(agent.Intents, intent)
+		}
+		for _, entity := range spec.Entities {
+			if _, exists := agent.Entity(entity.Name); exists {
+				return nil, fmt.Errorf("duplicated entity '%s' in spec '%s'", entity.Name, file)
+			}
+			agent.Entities = append(agent.Entities, entity)
+		}
+	}
+	if len(agent.Language) <= 0 {
+		agent.Language = DefaultLanguage
+	}
+
+	agent.sort()
+
+	return agent, nil
+}
+
+// Check if given file is a spec file.
+func isSpecFile(p string) bool {
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// Read a spec file.
+func readSpec(file string) (spec Spec, err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(file); err != nil {
+		return spec, err
+	}
+
+	if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJson(data); err != nil {
+			return spec, err
+		}
+	}
+
+	err = json.Unmarshal(data, &spec)
+	return spec, err
+}
+
+// Convert YAML into JSON, so that json tags of types can be used.
+func yamlToJson(data []byte) ([]byte, error) {
+	var obj interface{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	converted, err := jsonCompatible(obj)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+// Convert YAML maps (with interface{} keys) into JSON-compatible ones.
+func jsonCompatible(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		obj := map[string]interface{}{}
+		for k, child := range v {
+			converted, err := jsonCompatible(child)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprintf("%v", k)] = converted
+		}
+		return obj, nil
+	case []interface{}:
+		list := []interface{}{}
+		for _, child := range v {
+			converted, err := jsonCompatible(child)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,451 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Type of plan actions.
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// Kind of resources of plan actions.
+type ResourceKind string
+
+const (
+	ResourceIntent  ResourceKind = "intent"
+	ResourceEntity  ResourceKind = "entity"
+	ResourceEntries ResourceKind = "entries" // entries of an entity
+)
+
+// An action of a plan.
+type Action struct {
+	Type     ActionType
+	Resource ResourceKind
+	Name     string   // name of the intent or entity
+	Id       string   // id of the live intent or entity (empty on creation)
+	Details  []string // descriptions of changes
+
+	intent  df.IntentObject
+	entity  df.EntityObject
+	entries []df.EntityEntryObject
+	values  []string // entry values to delete
+}
+
+// Describe this action in a line.
+func (a Action) String() string {
+	var sign string
+	switch a.Type {
+	case ActionCreate:
+		sign = "+"
+	case ActionUpdate:
+		sign = "~"
+	case ActionDelete:
+		sign = "-"
+	}
+
+	line := fmt.Sprintf("%s %s %s '%s'", sign, a.Type, a.Resource, a.Name)
+	if len(a.Details) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(a.Details, ", "))
+	}
+	return line
+}
+
+// Plan of actions for syncing a live agent to a desired one.
+type Plan struct {
+	Actions []Action
+}
+
+// Options for planning.
+type PlanOptions struct {
+	Prune bool // delete intents and entities which are not in the desired agent
+}
+
+// Check if there is nothing to do.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) <= 0
+}
+
+// Count actions of given type.
+func (p *Plan) Count(typ ActionType) (count int) {
+	for _, action := range p.Actions {
+		if action.Type == typ {
+			count++
+		}
+	}
+	return count
+}
+
+// Print this plan.
+func (p *Plan) Print(w io.Writer) {
+	if p.Empty() {
+		fmt.Fprintln(w, "No changes. Live agent is up to date.")
+		return
+	}
+
+	for _, action := range p.Actions {
+		fmt.Fprintln(w, action.String())
+	}
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to delete.\n", p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+}
+
+// Fetch the live agent with given client and make a plan for syncing it to the desired one.
+func MakePlan(ctx context.Context, client *df.Client, desired *Agent, options PlanOptions) (*Plan, error) {
+	live, err := Fetch(ctx, client, desired.Language)
+	if err != nil {
+		return nil, err
+	}
+	return ComputePlan(live, desired, options), nil
+}
+
+// Compute a plan for syncing the live agent to the desired one.
+//
+// Only fields which are given in the desired agent are compared,
+// so fields filled by the server do not make differences.
+// (on update, fields of intents which are not given are kept as the live ones)
+func ComputePlan(live, desired *Agent, options PlanOptions) *Plan {
+	plan := &Plan{Actions: []Action{}}
+
+	// entities
+	for _, entity := range desired.Entities {
+		liveEntity, exists := live.Entity(entity.Name)
+		if !exists {
+			plan.Actions = append(plan.Actions, Action{
+				Type:     ActionCreate,
+				Resource: ResourceEntity,
+				Name:     entity.Name,
+				Details:  []string{fmt.Sprintf("%d entries", len(entity.Entries))},
+				entity:   entity,
+			})
+
+			// attributes cannot be set on creation, so update them after it
+			if entity.IsEnum || entity.AutomatedExpansion {
+				plan.Actions = append(plan.Actions, Action{
+					Type:     ActionUpdate,
+					Resource: ResourceEntity,
+					Name:     entity.Name,
+					Details:  []string{"attributes"},
+					entity:   entity,
+				})
+			}
+			continue
+		}
+
+		if entity.IsEnum != liveEntity.IsEnum || entity.AutomatedExpansion != liveEntity.AutomatedExpansion {
+			plan.Actions = append(plan.Actions, Action{
+				Type:     ActionUpdate,
+				Resource: ResourceEntity,
+				Name:     entity.Name,
+				Id:       liveEntity.Id,
+				Details:  []string{"attributes"},
+				entity:   entity,
+			})
+		}
+		plan.Actions = append(plan.Actions, entryActions(liveEntity, entity)...)
+	}
+
+	// intents
+	for _, intent := range desired.Intents {
+		liveIntent, exists := live.Intent(intent.Name)
+		if !exists {
+			plan.Actions = append(plan.Actions, Action{
+				Type:     ActionCreate,
+				Resource: ResourceIntent,
+				Name:     intent.Name,
+				intent:   intent,
+			})
+			continue
+		}
+
+		if fields := changedFields(intent, liveIntent); len(fields) > 0 {
+			plan.Actions = append(plan.Actions, Action{
+				Type:     ActionUpdate,
+				Resource: ResourceIntent,
+				Name:     intent.Name,
+				Id:       liveIntent.Id,
+				Details:  fields,
+				intent:   mergedIntent(intent, liveIntent),
+			})
+		}
+	}
+
+	if options.Prune {
+		// intents before entities which they may reference
+		for _, intent := range live.Intents {
+			if _, exists := desired.Intent(intent.Name); !exists {
+				plan.Actions = append(plan.Actions, Action{
+					Type:     ActionDelete,
+					Resource: ResourceIntent,
+					Name:     intent.Name,
+					Id:       intent.Id,
+				})
+			}
+		}
+		for _, entity := range live.Entities {
+			if _, exists := desired.Entity(entity.Name); !exists {
+				plan.Actions = append(plan.Actions, Action{
+					Type:     ActionDelete,
+					Resource: ResourceEntity,
+					Name:     entity.Name,
+					Id:       entity.Id,
+				})
+			}
+		}
+	}
+
+	return plan
+}
+
+// Compute actions for entries of an entity.
+func entryActions(live, desired df.EntityObject) (actions []Action) {
+	liveEntries := map[string]df.EntityEntryObject{}
+	for _, entry := range live.Entries {
+		liveEntries[entry.Value] = entry
+	}
+	desiredValues := map[string]bool{}
+
+	added, changed := []df.EntityEntryObject{}, []df.EntityEntryObject{}
+	for _, entry := range desired.Entries {
+		desiredValues[entry.Value] = true
+
+		if liveEntry, exists := liveEntries[entry.Value]; !exists {
+			added = append(added, entry)
+		} else if !reflect.DeepEqual(normalizeSynonyms(liveEntry), normalizeSynonyms(entry)) {
+			changed = append(changed, entry)
+		}
+	}
+	removed := []string{}
+	for _, entry := range live.Entries {
+		if !desiredValues[entry.Value] {
+			removed = append(removed, entry.Value)
+		}
+	}
+
+	if len(added) > 0 {
+		actions = append(actions, Action{
+			Type:     ActionCreate,
+			Resource: ResourceEntries,
+			Name:     desired.Name,
+			Id:       live.Id,
+			Details:  entryValues(added),
+			entries:  added,
+		})
+	}
+	if len(changed) > 0 {
+		actions = append(actions, Action{
+			Type:     ActionUpdate,
+			Resource: ResourceEntries,
+			Name:     desired.Name,
+			Id:       live.Id,
+			Details:  entryValues(changed),
+			entries:  changed,
+		})
+	}
+	if len(removed) > 0 {
+		actions = append(actions, Action{
+			Type:     ActionDelete,
+			Resource: ResourceEntries,
+			Name:     desired.Name,
+			Id:       live.Id,
+			Details:  removed,
+			values:   removed,
+		})
+	}
+
+	return actions
+}
+
+// Get synonyms of an entry, treating nil as empty.
+func normalizeSynonyms(entry df.EntityEntryObject) []string {
+	if entry.Synonyms == nil {
+		return []string{}
+	}
+	return entry.Synonyms
+}
+
+// Get values of entries.
+func entryValues(entries []df.EntityEntryObject) []string {
+	values := []string{}
+	for _, entry := range entries {
+		values = append(values, entry.Value)
+	}
+	return values
+}
+
+// Get names of top-level fields of the desired intent which differ from the live one.
+func changedFields(desired, live df.IntentObject) []string {
+	d, err := toJsonObject(desired)
+	if err != nil {
+		return []string{"(unknown)"}
+	}
+	l, err := toJsonObject(live)
+	if err != nil {
+		return []string{"(unknown)"}
+	}
+
+	fields := []string{}
+	for _, key := range sortedKeys(d) {
+		if key == "id" || key == "status" {
+			continue
+		}
+		if !subsetEqual(d[key], l[key]) {
+			fields = append(fields, key)
+		}
+	}
+	return fields
+}
+
+// Get the live intent overwritten with top-level fields given in the desired one.
+//
+// (updating an intent replaces it, so fields not given in the desired one would be cleared otherwise)
+func mergedIntent(desired, live df.IntentObject) (merged df.IntentObject) {
+	d, err := toJsonObject(desired)
+	if err != nil {
+		return desired
+	}
+	l, err := toJsonObject(live)
+	if err != nil {
+		return desired
+	}
+
+	for key, value := range d {
+		l[key] = value
+	}
+
+	var data []byte
+	if data, err = json.Marshal(l); err == nil {
+		err = json.Unmarshal(data, &merged)
+	}
+	if err != nil {
+		return desired
+	}
+	return merged
+}
+
+// Check if all values given in desired one are equal to the live one.
+func subsetEqual(desired, live interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return len(d) <= 0 && live == nil
+		}
+		for k, v := range d {
+			if k == "id" { // ids of user says, etc.
+				continue
+			}
+			if !subsetEqual(v, l[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return len(d) <= 0 && live == nil
+		}
+		if len(d) != len(l) {
+			return false
+		}
+		for i := range d {
+			if !subsetEqual(d[i], l[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return true
+	}
+
+	return reflect.DeepEqual(desired, live) || (isZero(desired) && live == nil)
+}
+
+// Check if given json value is a zero value.
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	}
+	return false
+}
+
+// Apply this plan with given client.
+//
+// Returns the number of applied actions, which is less than the number of all actions on error.
+func (p *Plan) Apply(ctx context.Context, client *df.Client) (applied int, err error) {
+	createdEntityIds := map[string]string{} // key: entity name
+
+	for _, action := range p.Actions {
+		switch action.Resource {
+		case ResourceEntity:
+			switch action.Type {
+			case ActionCreate:
+				entity := action.entity
+				entity.ApiResponse = df.ApiResponse{}
+				entity.IsEnum, entity.AutomatedExpansion = false, false // should not be filled on creation
+
+				var res df.ApiResponse
+				if res, err = client.CreateEntityContext(ctx, entity); err == nil {
+					createdEntityIds[entity.Name] = res.Id
+				}
+			case ActionUpdate:
+				entity := action.entity
+				entity.ApiResponse = df.ApiResponse{}
+
+				eid := action.Id
+				if len(eid) <= 0 { // entity created in this plan
+					if eid = createdEntityIds[action.Name]; len(eid) <= 0 {
+						eid = action.Name
+					}
+				}
+				_, err = client.UpdateEntityContext(ctx, eid, entity)
+			case ActionDelete:
+				_, err = client.DeleteEntityContext(ctx, action.Id)
+			}
+		case ResourceEntries:
+			switch action.Type {
+			case ActionCreate:
+				_, err = client.AddEntityEntriesContext(ctx, action.Id, action.entries)
+			case ActionUpdate:
+				_, err = client.UpdateEntityEntriesContext(ctx, action.Id, action.entries)
+			case ActionDelete:
+				_, err = client.DeleteEntityEntriesContext(ctx, action.Id, action.values)
+			}
+		case ResourceIntent:
+			switch action.Type {
+			case ActionCreate:
+				intent := action.intent
+				intent.ApiResponse = df.ApiResponse{}
+				_, err = client.CreateIntentContext(ctx, intent)
+			case ActionUpdate:
+				intent := action.intent
+				intent.ApiResponse = df.ApiResponse{}
+				_, err = client.UpdateIntentContext(ctx, action.Id, intent)
+			case ActionDelete:
+				_, err = client.DeleteIntentContext(ctx, action.Id)
+			}
+		}
+
+		if err != nil {
+			return applied, fmt.Errorf("failed to %s %s '%s': %w", action.Type, action.Resource, action.Name, err)
+		}
+		applied++
+	}
+
+	return applied, nil
+}
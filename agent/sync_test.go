@@ -0,0 +1,195 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/agent"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestApplySetsEntityAttributesAfterCreation(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	// capture bodies of entity creations
+	created := []string{}
+	client := s.NewClient("token", df.WithMiddlewares(func(next df.Doer) df.Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/entities") {
+				body, _ := ioutil.ReadAll(req.Body)
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+				created = append(created, string(body))
+			}
+			return next(req)
+		}
+	}))
+
+	desired := &agent.Agent{
+		Language: df.English,
+		Entities: []df.EntityObject{
+			{Name: "size", IsEnum: true, AutomatedExpansion: true, Entries: []df.EntityEntryObject{{Value: "large", Synonyms: []string{"large"}}}},
+		},
+	}
+
+	ctx := context.Background()
+	plan, err := agent.MakePlan(ctx, client, desired, agent.PlanOptions{})
+	if err != nil {
+		t.Fatalf("failed to make a plan: %s", err)
+	}
+	if len(plan.Actions) != 2 || plan.Actions[0].Type != agent.ActionCreate || plan.Actions[1].Type != agent.ActionUpdate {
+		t.Fatalf("expected creation and update of the entity, got %v", plan.Actions)
+	}
+	if _, err = plan.Apply(ctx, client); err != nil {
+		t.Fatalf("failed to apply: %s", err)
+	}
+
+	if len(created) != 1 || strings.Contains(created[0], "isEnum") || strings.Contains(created[0], "automatedExpansion") {
+		t.Errorf("expected attributes not to be filled on creation, got %v", created)
+	}
+	if entities := s.Entities(); len(entities) != 1 || !entities[0].IsEnum || !entities[0].AutomatedExpansion {
+		t.Errorf("expected attributes to be updated, got %+v", entities)
+	}
+}
+
+func TestSecondPlanAfterApplyIsEmpty(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.AddIntent(df.IntentObject{Name: "old"})
+	s.AddIntent(df.IntentObject{Name: "greet", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "hi"}}}}})
+	s.AddEntity(df.EntityObject{Name: "hero", Entries: []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog"}},
+		{Value: "Genji", Synonyms: []string{"Genji"}},
+	}})
+	s.AddEntity(df.EntityObject{Name: "stale"})
+
+	dir := t.TempDir()
+	writeFile(t, dir+"/agent.yaml", `
+language: en
+entities:
+  - name: hero
+    entries:
+      - value: Roadhog
+        synonyms: [Roadhog, pig]
+      - value: Mercy
+        synonyms: [Mercy, angel]
+intents:
+  - name: order
+    userSays:
+      - data:
+          - text: I want a pizza
+    responses:
+      - action: order
+        messages:
+          - type: 0
+            speech: ok
+`)
+	writeFile(t, dir+"/greet.json", `{"intents":[{"name":"greet","userSays":[{"data":[{"text":"hello"}]}]}]}`)
+
+	desired, err := agent.LoadSpec(dir)
+	if err != nil {
+		t.Fatalf("failed to load spec: %s", err)
+	}
+
+	ctx := context.Background()
+	client := s.NewClient("token")
+	options := agent.PlanOptions{Prune: true}
+
+	plan, err := agent.MakePlan(ctx, client, desired, options)
+	if err != nil {
+		t.Fatalf("failed to make a plan: %s", err)
+	}
+	if plan.Count(agent.ActionCreate) != 2 || plan.Count(agent.ActionUpdate) != 2 || plan.Count(agent.ActionDelete) != 3 {
+		var buf bytes.Buffer
+		plan.Print(&buf)
+		t.Errorf("unexpected plan:\n%s", buf.String())
+	}
+	if applied, err := plan.Apply(ctx, client); err != nil || applied != len(plan.Actions) {
+		t.Fatalf("failed to apply (%d of %d applied): %v", applied, len(plan.Actions), err)
+	}
+
+	if plan, err = agent.MakePlan(ctx, client, desired, options); err != nil {
+		t.Fatalf("failed to make the second plan: %s", err)
+	}
+	if !plan.Empty() {
+		var buf bytes.Buffer
+		plan.Print(&buf)
+		t.Errorf("expected the second plan to be empty, got:\n%s", buf.String())
+	}
+}
+
+func TestPlanWithoutPruneKeepsOthers(t *testing.T) {
+	live := &agent.Agent{Intents: []df.IntentObject{{Name: "other"}}, Entities: []df.EntityObject{{Name: "other"}}}
+	desired := &agent.Agent{}
+
+	if plan := agent.ComputePlan(live, desired, agent.PlanOptions{}); !plan.Empty() {
+		t.Errorf("expected an empty plan without prune, got %v", plan.Actions)
+	}
+	if plan := agent.ComputePlan(live, desired, agent.PlanOptions{Prune: true}); plan.Count(agent.ActionDelete) != 2 {
+		t.Errorf("expected 2 deletions with prune, got %v", plan.Actions)
+	}
+}
+
+func TestApplyKeepsUnspecifiedIntentFields(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.AddIntent(df.IntentObject{
+		Name:     "greet",
+		Contexts: []string{"welcome"},
+		UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "hi"}}}},
+		Responses: []df.IntentResponse{{
+			Action:           "greet",
+			AffectedContexts: []df.IntentAffectedContext{{Name: "greeted", Lifespan: 2}},
+			Messages:         []df.Message{df.NewMessage(df.TextResponseMessageObject{Speech: []string{"hello there"}})},
+		}},
+	})
+
+	// spec without contexts and responses
+	desired := &agent.Agent{
+		Language: df.English,
+		Intents: []df.IntentObject{
+			{Name: "greet", UserSays: []df.UserSays{{Data: []df.UserSaysData{{Text: "hello"}}}}},
+		},
+	}
+
+	ctx := context.Background()
+	client := s.NewClient("token")
+
+	plan, err := agent.MakePlan(ctx, client, desired, agent.PlanOptions{})
+	if err != nil {
+		t.Fatalf("failed to make a plan: %s", err)
+	}
+	if len(plan.Actions) != 1 || plan.Actions[0].Type != agent.ActionUpdate || strings.Join(plan.Actions[0].Details, ",") != "userSays" {
+		t.Fatalf("expected an update of user says, got %v", plan.Actions)
+	}
+	if _, err = plan.Apply(ctx, client); err != nil {
+		t.Fatalf("failed to apply: %s", err)
+	}
+
+	intents := s.Intents()
+	if len(intents) != 1 {
+		t.Fatalf("expected 1 intent, got %+v", intents)
+	}
+	intent := intents[0]
+	if len(intent.UserSays) != 1 || intent.UserSays[0].Data[0].Text != "hello" {
+		t.Errorf("expected user says to be updated, got %+v", intent.UserSays)
+	}
+	if len(intent.Contexts) != 1 || len(intent.Responses) != 1 || intent.Responses[0].Action != "greet" ||
+		len(intent.Responses[0].AffectedContexts) != 1 || len(intent.Responses[0].Messages) != 1 {
+		t.Errorf("expected unspecified fields to be kept, got %+v", intent)
+	}
+}
+
+// Write a file for tests.
+func writeFile(t *testing.T, p, content string) {
+	if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %s", p, err)
+	}
+}
@@ -0,0 +1,117 @@
+package dialogflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Required format of voice queries.
+const (
+	VoiceSampleRate    = 16000
+	VoiceBitsPerSample = 16
+	VoiceChannels      = 1
+)
+
+// Error returned when the audio of a voice query is not in the required format.
+var ErrUnsupportedAudio = errors.New("unsupported audio format (16000Hz, signed 16 bit PCM, mono .wav required)")
+
+const (
+	wavFormatPCM        = 1
+	wavFormatExtensible = 0xfffe
+
+	maxWavHeaderChunks = 16
+	maxWavSkippedChunk = 1 << 20 // maximum size of a chunk before 'fmt ', which is buffered for replaying
+)
+
+// SubFormat GUID of PCM in WAVE_FORMAT_EXTENSIBLE. (KSDATAFORMAT_SUBTYPE_PCM)
+var wavSubFormatPCM = []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71}
+
+// Validate that given reader has 16kHz, 16 bit, mono, signed PCM wav data.
+//
+// Returns a reader which replays the consumed header and the rest of the data.
+func validateWav(r io.Reader) (io.Reader, error) {
+	if r == nil {
+		return nil, fmt.Errorf("%w: no audio", ErrUnsupportedAudio)
+	}
+
+	var consumed bytes.Buffer
+	tee := io.TeeReader(r, &consumed)
+
+	// RIFF header
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(tee, header); err != nil {
+		return nil, fmt.Errorf("%w: failed to read wav header: %s", ErrUnsupportedAudio, err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%w: not a RIFF/WAVE file", ErrUnsupportedAudio)
+	}
+
+	// find 'fmt ' chunk
+	for i := 0; i < maxWavHeaderChunks; i++ {
+		chunk := make([]byte, 8)
+		if _, err := io.ReadFull(tee, chunk); err != nil {
+			return nil, fmt.Errorf("%w: failed to read wav chunk: %s", ErrUnsupportedAudio, err)
+		}
+		id, size := string(chunk[0:4]), binary.LittleEndian.Uint32(chunk[4:8])
+
+		if id != "fmt " {
+			// skip this chunk (padded to even size)
+			skip := int64(size) + int64(size%2)
+			if skip > maxWavSkippedChunk {
+				return nil, fmt.Errorf("%w: too large '%s' chunk before fmt chunk: %d", ErrUnsupportedAudio, id, size)
+			}
+			if _, err := io.CopyN(io.Discard, tee, skip); err != nil {
+				return nil, fmt.Errorf("%w: failed to read wav chunk: %s", ErrUnsupportedAudio, err)
+			}
+			continue
+		}
+
+		// read only the fixed part of the fmt chunk (the rest is replayed along with the data)
+		if size < 16 {
+			return nil, fmt.Errorf("%w: invalid fmt chunk size: %d", ErrUnsupportedAudio, size)
+		}
+		format := make([]byte, 16)
+		if _, err := io.ReadFull(tee, format); err != nil {
+			return nil, fmt.Errorf("%w: failed to read fmt chunk: %s", ErrUnsupportedAudio, err)
+		}
+
+		audioFormat := binary.LittleEndian.Uint16(format[0:2])
+		channels := binary.LittleEndian.Uint16(format[2:4])
+		sampleRate := binary.LittleEndian.Uint32(format[4:8])
+		bitsPerSample := binary.LittleEndian.Uint16(format[14:16])
+
+		switch audioFormat {
+		case wavFormatPCM:
+		case wavFormatExtensible:
+			// cbSize(2), valid bits per sample(2), channel mask(4), and SubFormat GUID(16)
+			if size < 16+24 {
+				return nil, fmt.Errorf("%w: invalid extensible fmt chunk size: %d", ErrUnsupportedAudio, size)
+			}
+			extension := make([]byte, 24)
+			if _, err := io.ReadFull(tee, extension); err != nil {
+				return nil, fmt.Errorf("%w: failed to read fmt chunk: %s", ErrUnsupportedAudio, err)
+			}
+			if !bytes.Equal(extension[8:24], wavSubFormatPCM) {
+				return nil, fmt.Errorf("%w: audio sub format is not PCM", ErrUnsupportedAudio)
+			}
+		default:
+			return nil, fmt.Errorf("%w: audio format is not PCM: %d", ErrUnsupportedAudio, audioFormat)
+		}
+		if sampleRate != VoiceSampleRate {
+			return nil, fmt.Errorf("%w: sample rate is %dHz", ErrUnsupportedAudio, sampleRate)
+		}
+		if bitsPerSample != VoiceBitsPerSample {
+			return nil, fmt.Errorf("%w: %d bits per sample", ErrUnsupportedAudio, bitsPerSample)
+		}
+		if channels != VoiceChannels {
+			return nil, fmt.Errorf("%w: %d channels", ErrUnsupportedAudio, channels)
+		}
+
+		return io.MultiReader(&consumed, r), nil
+	}
+
+	return nil, fmt.Errorf("%w: no fmt chunk", ErrUnsupportedAudio)
+}
@@ -0,0 +1,91 @@
+package dialogflow_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestQueryVoiceRejectsUnsupportedAudio(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	client := s.NewClient("token")
+
+	for name, data := range map[string][]byte{
+		"empty":       {},
+		"not wav":     []byte("ID3\x03\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+		"truncated":   validWav()[:20],
+		"float":       wavData(3, 1, 16000, 32, 100),
+		"44100Hz":     wavData(1, 1, 44100, 16, 100),
+		"8 bit":       wavData(1, 1, 16000, 8, 100),
+		"stereo":      wavData(1, 2, 16000, 16, 100),
+		"no fmt":      append([]byte("RIFF\x04\x00\x00\x00WAVE"), []byte("data\x00\x00\x00\x00")...),
+		"small chunk": append([]byte("RIFF\x04\x00\x00\x00WAVE"), []byte("fmt \x04\x00\x00\x00\x01\x00\x01\x00")...),
+		"overflow":    []byte("RIFF\x04\x00\x00\x00WAVEfmt \xff\xff\xff\xff"),
+		"huge fmt":    []byte("RIFF\x04\x00\x00\x00WAVEfmt \xfe\xff\xff\xff"),
+		"huge chunk":  []byte("RIFF\x04\x00\x00\x00WAVELIST\xff\xff\xff\xff"),
+		"float ext":   extensibleWav(wavSubFormatFloat),
+		"short ext":   wavData(0xfffe, 1, 16000, 16, 100),
+	} {
+		_, err := client.QueryVoice(df.QueryRequest{SessionId: "session"}, bytes.NewReader(data))
+		if !errors.Is(err, df.ErrUnsupportedAudio) {
+			t.Errorf("%s: expected ErrUnsupportedAudio, got %v", name, err)
+		}
+	}
+
+	if _, err := client.QueryVoice(df.QueryRequest{SessionId: "session"}, nil); !errors.Is(err, df.ErrUnsupportedAudio) {
+		t.Errorf("nil: expected ErrUnsupportedAudio, got %v", err)
+	}
+
+	if requests := s.Requests(); len(requests) > 0 {
+		t.Errorf("expected no request for unsupported audio, got %v", requests)
+	}
+}
+
+// SubFormat GUIDs of WAVE_FORMAT_EXTENSIBLE.
+var (
+	wavSubFormatPCM   = []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71}
+	wavSubFormatFloat = []byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71}
+)
+
+// Generate 16kHz, 16 bit, mono wav data in WAVE_FORMAT_EXTENSIBLE with given SubFormat GUID.
+func extensibleWav(subFormat []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF\x00\x00\x00\x00WAVE")
+	buf.WriteString("fmt \x28\x00\x00\x00")                           // size: 40
+	buf.Write([]byte{0xfe, 0xff, 0x01, 0x00})                         // format, channels
+	buf.Write([]byte{0x80, 0x3e, 0x00, 0x00, 0x00, 0x7d, 0x00, 0x00}) // sample rate, byte rate
+	buf.Write([]byte{0x02, 0x00, 0x10, 0x00})                         // block align, bits per sample
+	buf.Write([]byte{0x16, 0x00, 0x10, 0x00, 0x04, 0x00, 0x00, 0x00}) // cbSize, valid bits, channel mask
+	buf.Write(subFormat)
+	buf.WriteString("data\x00\x00\x00\x00")
+	return buf.Bytes()
+}
+
+func TestQueryVoiceAcceptsExtensiblePCM(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	if _, err := s.NewClient("token").QueryVoice(df.QueryRequest{SessionId: "session"}, bytes.NewReader(extensibleWav(wavSubFormatPCM))); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
+
+func TestQueryVoiceSkipsUnknownChunks(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	// insert a 'LIST' chunk (odd size, padded) before 'fmt '
+	wav := validWav()
+	data := append([]byte{}, wav[:12]...)
+	data = append(data, []byte("LIST\x03\x00\x00\x00abc\x00")...)
+	data = append(data, wav[12:]...)
+
+	if _, err := s.NewClient("token").QueryVoice(df.QueryRequest{SessionId: "session"}, bytes.NewReader(data)); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
@@ -0,0 +1,544 @@
+package dialogflow
+
+// Builders for fulfillment messages.
+//
+// (built messages can be used in IntentResponse.Messages)
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits of message objects.
+const (
+	MaxCardButtons       = 3  // buttons of a card
+	MaxQuickReplies      = 11 // replies of quick replies
+	MaxQuickReplyLength  = 20 // characters of a quick reply
+	MaxBasicCardButtons  = 1  // buttons of a basic card
+	MaxSuggestionChips   = 8  // suggestions of suggestion chips
+	MaxSuggestionLength  = 25 // characters of a suggestion
+	MinListCardItems     = 2  // items of a list card
+	MaxListCardItems     = 30
+	MinCarouselCardItems = 2 // items of a carousel card
+	MaxCarouselCardItems = 10
+)
+
+// Error returned when a built message is not valid.
+var ErrInvalidMessage = errors.New("invalid message")
+
+func invalidMessage(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidMessage, fmt.Sprintf(format, args...))
+}
+
+// Builder of a message.
+type MessageBuilder interface {
+	Build() (Message, error)
+}
+
+// Build messages with given builders.
+func BuildMessages(builders ...MessageBuilder) (messages []Message, err error) {
+	messages = []Message{}
+	for _, builder := range builders {
+		var message Message
+		if message, err = builder.Build(); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+///////////////////////////////
+//
+// text response
+
+type TextResponseMessageBuilder struct {
+	object TextResponseMessageObject
+}
+
+// Get a new builder for a text response message with speech variants.
+func NewTextResponseMessageBuilder(speech ...string) *TextResponseMessageBuilder {
+	return &TextResponseMessageBuilder{
+		object: TextResponseMessageObject{
+			MessageObject: MessageObject{Type: TextResponseMessageObjectType},
+			Speech:        speech,
+		},
+	}
+}
+
+// Set target platform.
+func (b *TextResponseMessageBuilder) Platform(platform string) *TextResponseMessageBuilder {
+	b.object.Platform = platform
+	return b
+}
+
+// Add a speech variant.
+func (b *TextResponseMessageBuilder) Speech(speech string) *TextResponseMessageBuilder {
+	b.object.Speech = append(b.object.Speech, speech)
+	return b
+}
+
+// Build a message.
+func (b *TextResponseMessageBuilder) Build() (Message, error) {
+	if len(b.object.Speech) <= 0 {
+		return Message{}, invalidMessage("text response has no speech")
+	}
+	for _, speech := range b.object.Speech {
+		if len(speech) <= 0 {
+			return Message{}, invalidMessage("text response has an empty speech")
+		}
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// card
+
+type CardMessageBuilder struct {
+	object CardMessageObject
+}
+
+// Get a new builder for a card message.
+func NewCardMessageBuilder(title string) *CardMessageBuilder {
+	return &CardMessageBuilder{
+		object: CardMessageObject{
+			MessageObject: MessageObject{Type: CardMessageObjectType},
+			Title:         title,
+			Buttons:       []CardMessageButton{},
+		},
+	}
+}
+
+// Set target platform.
+func (b *CardMessageBuilder) Platform(platform string) *CardMessageBuilder {
+	b.object.Platform = platform
+	return b
+}
+
+// Set subtitle.
+func (b *CardMessageBuilder) Subtitle(subtitle string) *CardMessageBuilder {
+	b.object.Subtitle = subtitle
+	return b
+}
+
+// Set image url.
+func (b *CardMessageBuilder) ImageUrl(imageUrl string) *CardMessageBuilder {
+	b.object.ImageUrl = imageUrl
+	return b
+}
+
+// Add a button.
+func (b *CardMessageBuilder) Button(text, postback string) *CardMessageBuilder {
+	b.object.Buttons = append(b.object.Buttons, CardMessageButton{
+		Text:     text,
+		Postback: postback,
+	})
+	return b
+}
+
+// Build a message.
+func (b *CardMessageBuilder) Build() (Message, error) {
+	if len(b.object.Title) <= 0 {
+		return Message{}, invalidMessage("card has no title")
+	}
+	if len(b.object.Buttons) > MaxCardButtons {
+		return Message{}, invalidMessage("card has %d buttons (max: %d)", len(b.object.Buttons), MaxCardButtons)
+	}
+	for _, button := range b.object.Buttons {
+		if len(button.Text) <= 0 {
+			return Message{}, invalidMessage("card has a button without text")
+		}
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// quick replies
+
+type QuickRepliesMessageBuilder struct {
+	object QuickRepliesMessageObject
+}
+
+// Get a new builder for a quick replies message.
+func NewQuickRepliesMessageBuilder(title string, replies ...string) *QuickRepliesMessageBuilder {
+	return &QuickRepliesMessageBuilder{
+		object: QuickRepliesMessageObject{
+			MessageObject: MessageObject{Type: QuickRepliesMessageObjectType},
+			Title:         title,
+			Replies:       append([]string{}, replies...),
+		},
+	}
+}
+
+// Set target platform.
+func (b *QuickRepliesMessageBuilder) Platform(platform string) *QuickRepliesMessageBuilder {
+	b.object.Platform = platform
+	return b
+}
+
+// Add a reply.
+func (b *QuickRepliesMessageBuilder) Reply(reply string) *QuickRepliesMessageBuilder {
+	b.object.Replies = append(b.object.Replies, reply)
+	return b
+}
+
+// Build a message.
+func (b *QuickRepliesMessageBuilder) Build() (Message, error) {
+	if len(b.object.Replies) <= 0 {
+		return Message{}, invalidMessage("quick replies has no reply")
+	}
+	if len(b.object.Replies) > MaxQuickReplies {
+		return Message{}, invalidMessage("quick replies has %d replies (max: %d)", len(b.object.Replies), MaxQuickReplies)
+	}
+	for _, reply := range b.object.Replies {
+		if len(reply) <= 0 {
+			return Message{}, invalidMessage("quick replies has an empty reply")
+		}
+		if utf8.RuneCountInString(reply) > MaxQuickReplyLength {
+			return Message{}, invalidMessage("quick reply '%s' is longer than %d characters", reply, MaxQuickReplyLength)
+		}
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// image
+
+type ImageMessageBuilder struct {
+	object ImageMessageObject
+}
+
+// Get a new builder for an image message.
+func NewImageMessageBuilder(imageUrl string) *ImageMessageBuilder {
+	return &ImageMessageBuilder{
+		object: ImageMessageObject{
+			MessageObject: MessageObject{Type: ImageMessageObjectType},
+			ImageUrl:      imageUrl,
+		},
+	}
+}
+
+// Set target platform.
+func (b *ImageMessageBuilder) Platform(platform string) *ImageMessageBuilder {
+	b.object.Platform = platform
+	return b
+}
+
+// Build a message.
+func (b *ImageMessageBuilder) Build() (Message, error) {
+	if len(b.object.ImageUrl) <= 0 {
+		return Message{}, invalidMessage("image has no url")
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// custom payload
+
+type CustomPayloadMessageBuilder struct {
+	object CustomPayloadMessageObject
+}
+
+// Get a new builder for a custom payload message.
+func NewCustomPayloadMessageBuilder(payload interface{}) *CustomPayloadMessageBuilder {
+	return &CustomPayloadMessageBuilder{
+		object: CustomPayloadMessageObject{
+			MessageObject: MessageObject{Type: CustomPayloadMessageObjectType},
+			Payload:       payload,
+		},
+	}
+}
+
+// Set target platform.
+func (b *CustomPayloadMessageBuilder) Platform(platform string) *CustomPayloadMessageBuilder {
+	b.object.Platform = platform
+	return b
+}
+
+// Build a message.
+func (b *CustomPayloadMessageBuilder) Build() (Message, error) {
+	if b.object.Payload == nil {
+		return Message{}, invalidMessage("custom payload has no payload")
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// simple response (google)
+
+type SimpleResponseMessageBuilder struct {
+	object SimpleResponseMessageObject
+}
+
+// Get a new builder for a simple response message.
+func NewSimpleResponseMessageBuilder(textToSpeech string) *SimpleResponseMessageBuilder {
+	return &SimpleResponseMessageBuilder{
+		object: SimpleResponseMessageObject{
+			MessageObject: MessageObject{Type: SimpleResponseMessageObjectType, Platform: PlatformGoogle},
+			TextToSpeech:  textToSpeech,
+		},
+	}
+}
+
+// Set ssml. (used instead of text to speech)
+func (b *SimpleResponseMessageBuilder) Ssml(ssml string) *SimpleResponseMessageBuilder {
+	b.object.Ssml = ssml
+	return b
+}
+
+// Set display text.
+func (b *SimpleResponseMessageBuilder) DisplayText(displayText string) *SimpleResponseMessageBuilder {
+	b.object.DisplayText = displayText
+	return b
+}
+
+// Build a message.
+func (b *SimpleResponseMessageBuilder) Build() (Message, error) {
+	if len(b.object.TextToSpeech) <= 0 && len(b.object.Ssml) <= 0 {
+		return Message{}, invalidMessage("simple response has neither text to speech nor ssml")
+	}
+	if len(b.object.TextToSpeech) > 0 && len(b.object.Ssml) > 0 {
+		return Message{}, invalidMessage("simple response has both text to speech and ssml")
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// basic card (google)
+
+type BasicCardMessageBuilder struct {
+	object BasicCardMessageObject
+}
+
+// Get a new builder for a basic card message.
+func NewBasicCardMessageBuilder(title string) *BasicCardMessageBuilder {
+	return &BasicCardMessageBuilder{
+		object: BasicCardMessageObject{
+			MessageObject: MessageObject{Type: BasicCardMessageObjectType, Platform: PlatformGoogle},
+			Title:         title,
+		},
+	}
+}
+
+// Set subtitle.
+func (b *BasicCardMessageBuilder) Subtitle(subtitle string) *BasicCardMessageBuilder {
+	b.object.Subtitle = subtitle
+	return b
+}
+
+// Set formatted text.
+func (b *BasicCardMessageBuilder) FormattedText(formattedText string) *BasicCardMessageBuilder {
+	b.object.FormattedText = formattedText
+	return b
+}
+
+// Set image.
+func (b *BasicCardMessageBuilder) Image(url, accessibilityText string) *BasicCardMessageBuilder {
+	b.object.Image = &GoogleImage{
+		Url:               url,
+		AccessibilityText: accessibilityText,
+	}
+	return b
+}
+
+// Add a button which opens given url.
+func (b *BasicCardMessageBuilder) Button(title, url string) *BasicCardMessageBuilder {
+	b.object.Buttons = append(b.object.Buttons, BasicCardButton{
+		Title:         title,
+		OpenUrlAction: OpenUrlAction{Url: url},
+	})
+	return b
+}
+
+// Build a message.
+func (b *BasicCardMessageBuilder) Build() (Message, error) {
+	if len(b.object.FormattedText) <= 0 && b.object.Image == nil {
+		return Message{}, invalidMessage("basic card has neither formatted text nor image")
+	}
+	if b.object.Image != nil && len(b.object.Image.Url) <= 0 {
+		return Message{}, invalidMessage("basic card has an image without url")
+	}
+	if len(b.object.Buttons) > MaxBasicCardButtons {
+		return Message{}, invalidMessage("basic card has %d buttons (max: %d)", len(b.object.Buttons), MaxBasicCardButtons)
+	}
+	for _, button := range b.object.Buttons {
+		if len(button.Title) <= 0 || len(button.OpenUrlAction.Url) <= 0 {
+			return Message{}, invalidMessage("basic card has a button without title or url")
+		}
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// list card and carousel card (google)
+
+// Validate items of list or carousel cards.
+func validateSelectItems(name string, items []SelectItemInfo, min, max int) error {
+	if len(items) < min || len(items) > max {
+		return invalidMessage("%s has %d items (min: %d, max: %d)", name, len(items), min, max)
+	}
+
+	keys := map[string]bool{}
+	for _, item := range items {
+		if len(item.OptionInfo.Key) <= 0 || len(item.Title) <= 0 {
+			return invalidMessage("%s has an item without key or title", name)
+		}
+		if keys[item.OptionInfo.Key] {
+			return invalidMessage("%s has duplicated item key: %s", name, item.OptionInfo.Key)
+		}
+		keys[item.OptionInfo.Key] = true
+	}
+
+	return nil
+}
+
+type ListCardMessageBuilder struct {
+	object ListCardMessageObject
+}
+
+// Get a new builder for a list card message.
+func NewListCardMessageBuilder(title string) *ListCardMessageBuilder {
+	return &ListCardMessageBuilder{
+		object: ListCardMessageObject{
+			MessageObject: MessageObject{Type: ListCardMessageObjectType, Platform: PlatformGoogle},
+			Title:         title,
+			Items:         []SelectItemInfo{},
+		},
+	}
+}
+
+// Add an item.
+func (b *ListCardMessageBuilder) Item(item SelectItemInfo) *ListCardMessageBuilder {
+	b.object.Items = append(b.object.Items, item)
+	return b
+}
+
+// Build a message.
+func (b *ListCardMessageBuilder) Build() (Message, error) {
+	if err := validateSelectItems("list card", b.object.Items, MinListCardItems, MaxListCardItems); err != nil {
+		return Message{}, err
+	}
+
+	return NewMessage(b.object), nil
+}
+
+type CarouselCardMessageBuilder struct {
+	object CarouselCardMessageObject
+}
+
+// Get a new builder for a carousel card message.
+func NewCarouselCardMessageBuilder() *CarouselCardMessageBuilder {
+	return &CarouselCardMessageBuilder{
+		object: CarouselCardMessageObject{
+			MessageObject: MessageObject{Type: CarouselCardMessageObjectType, Platform: PlatformGoogle},
+			Items:         []SelectItemInfo{},
+		},
+	}
+}
+
+// Add an item.
+func (b *CarouselCardMessageBuilder) Item(item SelectItemInfo) *CarouselCardMessageBuilder {
+	b.object.Items = append(b.object.Items, item)
+	return b
+}
+
+// Build a message.
+func (b *CarouselCardMessageBuilder) Build() (Message, error) {
+	if err := validateSelectItems("carousel card", b.object.Items, MinCarouselCardItems, MaxCarouselCardItems); err != nil {
+		return Message{}, err
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// suggestion chips (google)
+
+type SuggestionChipsMessageBuilder struct {
+	object SuggestionChipsMessageObject
+}
+
+// Get a new builder for a suggestion chips message.
+func NewSuggestionChipsMessageBuilder(titles ...string) *SuggestionChipsMessageBuilder {
+	b := &SuggestionChipsMessageBuilder{
+		object: SuggestionChipsMessageObject{
+			MessageObject: MessageObject{Type: SuggestionChipsMessageObjectType, Platform: PlatformGoogle},
+			Suggestions:   []Suggestion{},
+		},
+	}
+	for _, title := range titles {
+		b.Suggestion(title)
+	}
+	return b
+}
+
+// Add a suggestion.
+func (b *SuggestionChipsMessageBuilder) Suggestion(title string) *SuggestionChipsMessageBuilder {
+	b.object.Suggestions = append(b.object.Suggestions, Suggestion{Title: title})
+	return b
+}
+
+// Build a message.
+func (b *SuggestionChipsMessageBuilder) Build() (Message, error) {
+	if len(b.object.Suggestions) <= 0 {
+		return Message{}, invalidMessage("suggestion chips has no suggestion")
+	}
+	if len(b.object.Suggestions) > MaxSuggestionChips {
+		return Message{}, invalidMessage("suggestion chips has %d suggestions (max: %d)", len(b.object.Suggestions), MaxSuggestionChips)
+	}
+	for _, suggestion := range b.object.Suggestions {
+		if len(suggestion.Title) <= 0 {
+			return Message{}, invalidMessage("suggestion chips has an empty suggestion")
+		}
+		if utf8.RuneCountInString(suggestion.Title) > MaxSuggestionLength {
+			return Message{}, invalidMessage("suggestion '%s' is longer than %d characters", suggestion.Title, MaxSuggestionLength)
+		}
+	}
+
+	return NewMessage(b.object), nil
+}
+
+///////////////////////////////
+//
+// link out chip (google)
+
+type LinkOutChipMessageBuilder struct {
+	object LinkOutChipMessageObject
+}
+
+// Get a new builder for a link out chip message.
+func NewLinkOutChipMessageBuilder(destinationName, url string) *LinkOutChipMessageBuilder {
+	return &LinkOutChipMessageBuilder{
+		object: LinkOutChipMessageObject{
+			MessageObject:   MessageObject{Type: LinkOutChipMessageObjectType, Platform: PlatformGoogle},
+			DestinationName: destinationName,
+			Url:             url,
+		},
+	}
+}
+
+// Build a message.
+func (b *LinkOutChipMessageBuilder) Build() (Message, error) {
+	if len(b.object.DestinationName) <= 0 || len(b.object.Url) <= 0 {
+		return Message{}, invalidMessage("link out chip has no destination name or url")
+	}
+
+	return NewMessage(b.object), nil
+}
@@ -0,0 +1,201 @@
+package dialogflow_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Generate select items with unique keys.
+func selectItems(n int) []df.SelectItemInfo {
+	items := []df.SelectItemInfo{}
+	for i := 0; i < n; i++ {
+		items = append(items, df.SelectItemInfo{
+			OptionInfo: df.OptionInfo{Key: fmt.Sprintf("key%d", i)},
+			Title:      fmt.Sprintf("Item %d", i),
+		})
+	}
+	return items
+}
+
+// Get a list card builder with given items.
+func listCard(items []df.SelectItemInfo) *df.ListCardMessageBuilder {
+	b := df.NewListCardMessageBuilder("List")
+	for _, item := range items {
+		b.Item(item)
+	}
+	return b
+}
+
+// Get a carousel card builder with given items.
+func carouselCard(items []df.SelectItemInfo) *df.CarouselCardMessageBuilder {
+	b := df.NewCarouselCardMessageBuilder()
+	for _, item := range items {
+		b.Item(item)
+	}
+	return b
+}
+
+// Get a card builder with n buttons.
+func cardWithButtons(n int) *df.CardMessageBuilder {
+	b := df.NewCardMessageBuilder("Card")
+	for i := 0; i < n; i++ {
+		b.Button(fmt.Sprintf("Button %d", i), "postback")
+	}
+	return b
+}
+
+// Generate n strings.
+func strs(n int, prefix string) []string {
+	s := []string{}
+	for i := 0; i < n; i++ {
+		s = append(s, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return s
+}
+
+func TestBuilders(t *testing.T) {
+	duplicated := selectItems(3)
+	duplicated[2].OptionInfo.Key = duplicated[0].OptionInfo.Key
+	untitled := selectItems(2)
+	untitled[1].Title = ""
+
+	for _, test := range []struct {
+		name    string
+		builder df.MessageBuilder
+		valid   bool
+		typ     df.MessageType
+	}{
+		// text response
+		{"text", df.NewTextResponseMessageBuilder("Hi", "Hello"), true, df.TextResponseMessageObjectType},
+		{"text without speech", df.NewTextResponseMessageBuilder(), false, 0},
+		{"text with empty speech", df.NewTextResponseMessageBuilder("Hi", ""), false, 0},
+
+		// card
+		{"card", cardWithButtons(df.MaxCardButtons).Subtitle("sub").ImageUrl("https://example.com/a.png"), true, df.CardMessageObjectType},
+		{"card without title", df.NewCardMessageBuilder(""), false, 0},
+		{"card with too many buttons", cardWithButtons(df.MaxCardButtons + 1), false, 0},
+		{"card with untitled button", df.NewCardMessageBuilder("Card").Button("", "postback"), false, 0},
+
+		// quick replies
+		{"quick replies", df.NewQuickRepliesMessageBuilder("Size?", strs(df.MaxQuickReplies, "r")...), true, df.QuickRepliesMessageObjectType},
+		{"quick replies without reply", df.NewQuickRepliesMessageBuilder("Size?"), false, 0},
+		{"too many quick replies", df.NewQuickRepliesMessageBuilder("Size?", strs(df.MaxQuickReplies+1, "r")...), false, 0},
+		{"empty quick reply", df.NewQuickRepliesMessageBuilder("Size?", "a", ""), false, 0},
+		{"quick reply of max runes", df.NewQuickRepliesMessageBuilder("Size?", strings.Repeat("가", df.MaxQuickReplyLength)), true, df.QuickRepliesMessageObjectType},
+		{"too long quick reply", df.NewQuickRepliesMessageBuilder("Size?", strings.Repeat("가", df.MaxQuickReplyLength+1)), false, 0},
+
+		// image, custom payload
+		{"image", df.NewImageMessageBuilder("https://example.com/a.png"), true, df.ImageMessageObjectType},
+		{"image without url", df.NewImageMessageBuilder(""), false, 0},
+		{"custom payload", df.NewCustomPayloadMessageBuilder(map[string]interface{}{"a": 1}), true, df.CustomPayloadMessageObjectType},
+		{"custom payload without payload", df.NewCustomPayloadMessageBuilder(nil), false, 0},
+
+		// simple response
+		{"simple response", df.NewSimpleResponseMessageBuilder("Hi").DisplayText("Hi!"), true, df.SimpleResponseMessageObjectType},
+		{"simple response with ssml", df.NewSimpleResponseMessageBuilder("").Ssml("<speak>Hi</speak>"), true, df.SimpleResponseMessageObjectType},
+		{"simple response with both", df.NewSimpleResponseMessageBuilder("Hi").Ssml("<speak>Hi</speak>"), false, 0},
+		{"simple response with neither", df.NewSimpleResponseMessageBuilder(""), false, 0},
+
+		// basic card
+		{"basic card", df.NewBasicCardMessageBuilder("Card").FormattedText("text").Button("Open", "https://example.com"), true, df.BasicCardMessageObjectType},
+		{"basic card with image", df.NewBasicCardMessageBuilder("Card").Image("https://example.com/a.png", "a"), true, df.BasicCardMessageObjectType},
+		{"basic card without text or image", df.NewBasicCardMessageBuilder("Card"), false, 0},
+		{"basic card with image without url", df.NewBasicCardMessageBuilder("Card").Image("", "a"), false, 0},
+		{"basic card with too many buttons", df.NewBasicCardMessageBuilder("Card").FormattedText("text").Button("A", "https://a").Button("B", "https://b"), false, 0},
+		{"basic card with button without url", df.NewBasicCardMessageBuilder("Card").FormattedText("text").Button("A", ""), false, 0},
+
+		// list card
+		{"list card of min items", listCard(selectItems(df.MinListCardItems)), true, df.ListCardMessageObjectType},
+		{"list card of max items", listCard(selectItems(df.MaxListCardItems)), true, df.ListCardMessageObjectType},
+		{"list card of too few items", listCard(selectItems(df.MinListCardItems - 1)), false, 0},
+		{"list card of too many items", listCard(selectItems(df.MaxListCardItems + 1)), false, 0},
+		{"list card with duplicated keys", listCard(duplicated), false, 0},
+		{"list card with untitled item", listCard(untitled), false, 0},
+
+		// carousel card
+		{"carousel card of min items", carouselCard(selectItems(df.MinCarouselCardItems)), true, df.CarouselCardMessageObjectType},
+		{"carousel card of max items", carouselCard(selectItems(df.MaxCarouselCardItems)), true, df.CarouselCardMessageObjectType},
+		{"carousel card of too few items", carouselCard(selectItems(df.MinCarouselCardItems - 1)), false, 0},
+		{"carousel card of too many items", carouselCard(selectItems(df.MaxCarouselCardItems + 1)), false, 0},
+		{"carousel card with duplicated keys", carouselCard(duplicated), false, 0},
+
+		// suggestion chips
+		{"suggestion chips", df.NewSuggestionChipsMessageBuilder(strs(df.MaxSuggestionChips, "s")...), true, df.SuggestionChipsMessageObjectType},
+		{"suggestion chips without suggestion", df.NewSuggestionChipsMessageBuilder(), false, 0},
+		{"too many suggestion chips", df.NewSuggestionChipsMessageBuilder(strs(df.MaxSuggestionChips+1, "s")...), false, 0},
+		{"empty suggestion", df.NewSuggestionChipsMessageBuilder("a", ""), false, 0},
+		{"suggestion of max runes", df.NewSuggestionChipsMessageBuilder(strings.Repeat("é", df.MaxSuggestionLength)), true, df.SuggestionChipsMessageObjectType},
+		{"too long suggestion", df.NewSuggestionChipsMessageBuilder(strings.Repeat("é", df.MaxSuggestionLength+1)), false, 0},
+
+		// link out chip
+		{"link out chip", df.NewLinkOutChipMessageBuilder("Site", "https://example.com"), true, df.LinkOutChipMessageObjectType},
+		{"link out chip without url", df.NewLinkOutChipMessageBuilder("Site", ""), false, 0},
+	} {
+		message, err := test.builder.Build()
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %s", test.name, err)
+			} else if message.Type() != test.typ {
+				t.Errorf("%s: expected type %s, got %s", test.name, test.typ, message.Type())
+			}
+		} else if !errors.Is(err, df.ErrInvalidMessage) {
+			t.Errorf("%s: expected ErrInvalidMessage, got %v", test.name, err)
+		}
+	}
+}
+
+func TestBuildMessages(t *testing.T) {
+	if _, err := df.BuildMessages(df.NewTextResponseMessageBuilder("Hi"), df.NewImageMessageBuilder("")); !errors.Is(err, df.ErrInvalidMessage) {
+		t.Errorf("expected ErrInvalidMessage, got %v", err)
+	}
+
+	messages, err := df.BuildMessages(
+		df.NewTextResponseMessageBuilder("Hi"),
+		df.NewCardMessageBuilder("Pizza").Platform(df.PlatformFacebook).Button("Order", "order pizza"),
+		df.NewQuickRepliesMessageBuilder("Size?", "Small", "Large"),
+		df.NewSimpleResponseMessageBuilder("Hi there"),
+		listCard(selectItems(2)),
+		df.NewSuggestionChipsMessageBuilder("Yes", "No"),
+	)
+	if err != nil {
+		t.Fatalf("failed to build messages: %s", err)
+	}
+
+	// round trip through the api
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	client := s.NewClient("token")
+	res, err := client.CreateIntent(df.IntentObject{
+		Name:      "order",
+		Responses: []df.IntentResponse{{Messages: messages}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create intent: %s", err)
+	}
+	intent, err := client.Intent(res.Id)
+	if err != nil {
+		t.Fatalf("failed to get intent: %s", err)
+	}
+
+	received := intent.Responses[0].Messages
+	if len(received) != len(messages) {
+		t.Fatalf("expected %d messages, got %d", len(messages), len(received))
+	}
+	for i := range messages {
+		if received[i].Type() != messages[i].Type() || received[i].Platform() != messages[i].Platform() {
+			t.Errorf("message %d: expected %s (%s), got %s (%s)", i, messages[i].Type(), messages[i].Platform(), received[i].Type(), received[i].Platform())
+		}
+	}
+	if card := received[1].ToCardMessage(); card.Title != "Pizza" || len(card.Buttons) != 1 || card.Buttons[0].Postback != "order pizza" {
+		t.Errorf("unexpected card: %+v", card)
+	}
+	if list := received[4].ToListCardMessage(); len(list.Items) != 2 || list.Items[1].OptionInfo.Key != "key1" {
+		t.Errorf("unexpected list card: %+v", list)
+	}
+}
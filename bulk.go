@@ -0,0 +1,196 @@
+package dialogflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default number of concurrent workers for bulk fetches.
+const DefaultBulkConcurrency = 4
+
+// Options for bulk fetches.
+type BulkOptions struct {
+	Concurrency int // number of concurrent workers (DefaultBulkConcurrency will be used if <= 0)
+
+	// called after each item is fetched or failed (calls are serialized, so it does not need to be goroutine-safe)
+	OnProgress func(done, total int, id string, err error)
+}
+
+// Error returned when some items of a bulk fetch failed.
+//
+// (successfully fetched items are returned along with it)
+type BulkError struct {
+	Total  int              // number of all items
+	Failed map[string]error // errors of failed items, keyed by their ids
+}
+
+// Error message.
+func (e *BulkError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := []string{}
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", id, e.Failed[id]))
+	}
+
+	return fmt.Sprintf("failed to fetch %d of %d item(s) (%s)", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap to errors of failed items.
+//
+// (so errors.Is can check, eg. ErrNotFound)
+func (e *BulkError) Unwrap() []error {
+	errs := []error{}
+	for _, err := range e.Failed {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Get all intents with their full details.
+//
+// (fetches intents concurrently, so it is much faster than calling Intent for each intent)
+func (c *Client) AllIntentObjects(options BulkOptions) (result []IntentObject, err error) {
+	return c.AllIntentObjectsContext(context.Background(), options)
+}
+
+// Get all intents with their full details, with given context.
+//
+// Returns successfully fetched intents and *BulkError when some of them failed.
+func (c *Client) AllIntentObjectsContext(ctx context.Context, options BulkOptions) (result []IntentObject, err error) {
+	var intents []Intent
+	if intents, err = c.AllIntentsContext(ctx); err != nil {
+		return []IntentObject{}, err
+	}
+
+	ids := []string{}
+	for _, intent := range intents {
+		ids = append(ids, intent.Id)
+	}
+
+	objs := make([]IntentObject, len(ids))
+	errs := bulkFetch(ctx, ids, options, func(ctx context.Context, i int) (err error) {
+		if objs[i], err = c.IntentContext(ctx, ids[i]); err == nil {
+			objs[i].Id = ids[i]
+		}
+		return err
+	})
+
+	result = []IntentObject{}
+	for i, fetchErr := range errs {
+		if fetchErr == nil {
+			result = append(result, objs[i])
+		}
+	}
+
+	return result, bulkErrorFor(ids, errs)
+}
+
+// Get all entities with their full details.
+//
+// (fetches entities concurrently, so it is much faster than calling Entity for each entity)
+func (c *Client) AllEntityObjects(options BulkOptions) (result []EntityObject, err error) {
+	return c.AllEntityObjectsContext(context.Background(), options)
+}
+
+// Get all entities with their full details, with given context.
+//
+// Returns successfully fetched entities and *BulkError when some of them failed.
+func (c *Client) AllEntityObjectsContext(ctx context.Context, options BulkOptions) (result []EntityObject, err error) {
+	var entities Entities
+	if entities, err = c.AllEntitiesContext(ctx); err != nil {
+		return []EntityObject{}, err
+	}
+
+	ids := []string{}
+	for _, entity := range entities.Entities {
+		ids = append(ids, entity.Id)
+	}
+
+	objs := make([]EntityObject, len(ids))
+	errs := bulkFetch(ctx, ids, options, func(ctx context.Context, i int) (err error) {
+		if objs[i], err = c.EntityContext(ctx, ids[i]); err == nil {
+			objs[i].Id = ids[i]
+		}
+		return err
+	})
+
+	result = []EntityObject{}
+	for i, fetchErr := range errs {
+		if fetchErr == nil {
+			result = append(result, objs[i])
+		}
+	}
+
+	return result, bulkErrorFor(ids, errs)
+}
+
+// Fetch items of given ids with a bounded worker pool.
+//
+// Returns errors of items in the same order of ids. (nil for succeeded ones)
+func bulkFetch(ctx context.Context, ids []string, options BulkOptions, fetch func(ctx context.Context, i int) error) (errs []error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	errs = make([]error, len(ids))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	done := 0
+
+	for w := 0; w < concurrency && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indices {
+				err := ctx.Err()
+				if err == nil {
+					err = fetch(ctx, i)
+				}
+				errs[i] = err
+
+				lock.Lock()
+				done++
+				if options.OnProgress != nil {
+					options.OnProgress(done, len(ids), ids[i], err)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for i := range ids {
+		indices <- i
+	}
+	close(indices)
+
+	wg.Wait()
+
+	return errs
+}
+
+// Get *BulkError from errors of a bulk fetch, or nil if nothing failed.
+func bulkErrorFor(ids []string, errs []error) error {
+	failed := map[string]error{}
+	for i, err := range errs {
+		if err != nil {
+			failed[ids[i]] = err
+		}
+	}
+	if len(failed) <= 0 {
+		return nil
+	}
+
+	return &BulkError{Total: len(ids), Failed: failed}
+}
@@ -0,0 +1,102 @@
+package dialogflow_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestAllIntentObjectsPartialFailure(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	ids := []string{}
+	for i := 0; i < 5; i++ {
+		ids = append(ids, s.AddIntent(df.IntentObject{Name: fmt.Sprintf("intent-%d", i)}))
+	}
+	failing := ids[2]
+	s.InjectError("GET", "intents/"+failing, http.StatusNotFound, df.NotFound, -1)
+
+	type progress struct {
+		done, total int
+		id          string
+		err         error
+	}
+	progresses := []progress{}
+
+	intents, err := s.NewClient("token").AllIntentObjects(df.BulkOptions{
+		Concurrency: 2,
+		OnProgress: func(done, total int, id string, err error) {
+			progresses = append(progresses, progress{done, total, id, err})
+		},
+	})
+
+	// partial results
+	if len(intents) != 4 {
+		t.Errorf("expected 4 intents, got %d", len(intents))
+	}
+	for _, intent := range intents {
+		if intent.Id == failing || len(intent.Name) <= 0 {
+			t.Errorf("unexpected intent: %+v", intent)
+		}
+	}
+
+	// error of the failed one
+	var bulkErr *df.BulkError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("expected *BulkError, got %v", err)
+	}
+	if bulkErr.Total != 5 || len(bulkErr.Failed) != 1 || bulkErr.Failed[failing] == nil {
+		t.Errorf("unexpected bulk error: %+v", bulkErr)
+	}
+	if !errors.Is(err, df.ErrNotFound) {
+		t.Errorf("expected errors.Is(err, ErrNotFound), got %v", err)
+	}
+
+	// progress
+	if len(progresses) != 5 {
+		t.Fatalf("expected 5 progress calls, got %d", len(progresses))
+	}
+	reported := []string{}
+	for i, p := range progresses {
+		if p.done != i+1 || p.total != 5 {
+			t.Errorf("progress #%d: unexpected %d/%d", i, p.done, p.total)
+		}
+		if (p.id == failing) != (p.err != nil) {
+			t.Errorf("progress #%d: unexpected error of %s: %v", i, p.id, p.err)
+		}
+		reported = append(reported, p.id)
+	}
+	sort.Strings(reported)
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	if fmt.Sprint(reported) != fmt.Sprint(sorted) {
+		t.Errorf("expected progress of %v, got %v", sorted, reported)
+	}
+}
+
+func TestAllEntityObjects(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.AddEntity(df.EntityObject{Name: "hero", Entries: []df.EntityEntryObject{{Value: "Roadhog", Synonyms: []string{"Roadhog"}}}})
+	s.AddEntity(df.EntityObject{Name: "food"})
+
+	entities, err := s.NewClient("token").AllEntityObjects(df.BulkOptions{})
+	if err != nil {
+		t.Fatalf("failed to fetch entities: %s", err)
+	}
+	if len(entities) != 2 {
+		t.Fatalf("expected 2 entities, got %+v", entities)
+	}
+	for _, entity := range entities {
+		if len(entity.Id) <= 0 || (entity.Name == "hero" && len(entity.Entries) != 1) {
+			t.Errorf("unexpected entity: %+v", entity)
+		}
+	}
+}
@@ -2,15 +2,17 @@ package dialogflow
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,19 +26,176 @@ const (
 type Client struct {
 	AccessToken string `json:"access_token"`
 	Verbose     bool   `json:"verbose"`
+	BaseUrl     string `json:"base_url"` // BaseUrl will be used if empty
+	Version     string `json:"version"`  // Version will be used if empty
+
+	httpClient   *http.Client
+	middlewares  []Middleware
+	retryPolicy  *RetryPolicy
+	queryLimiter *RateLimiter
+	agentLimiter *RateLimiter
+	logger       Logger
+	redaction    LogRedaction
+	recorder     *TranscriptRecorder
 }
 
-// Get a new api client with given access token.
-func NewClient(accessToken string) *Client {
-	return &Client{
+// Function which sends a http request and returns its response.
+type Doer func(req *http.Request) (*http.Response, error)
+
+// Middleware which wraps a Doer.
+//
+// (can be used for tracing, header injection, request signing, etc.)
+type Middleware func(next Doer) Doer
+
+// Option for NewClient.
+type ClientOption func(c *Client)
+
+// Option for setting the http client used for all requests.
+//
+// (timeouts, proxies, TLS configs, or custom RoundTrippers can be set on it)
+func WithHttpClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// Option for appending middlewares.
+//
+// (middlewares are applied in given order, so the first one sees the request first)
+func WithMiddlewares(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// Option for setting the base url of apis.
+//
+// (eg. for pointing to a local stand-in server or a proxy path)
+func WithBaseUrl(baseUrl string) ClientOption {
+	return func(c *Client) {
+		c.BaseUrl = strings.TrimSuffix(baseUrl, "/")
+	}
+}
+
+// Option for setting the protocol version.
+func WithVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.Version = version
+	}
+}
+
+// Get a new api client with given access token and options.
+func NewClient(accessToken string, options ...ClientOption) *Client {
+	c := &Client{
 		AccessToken: accessToken,
 		Verbose:     false,
+		BaseUrl:     BaseUrl,
+		Version:     Version,
+		httpClient:  &http.Client{},
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Send a http request through middlewares.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	var doer Doer = httpClient.Do
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		doer = c.middlewares[i](doer)
 	}
+
+	return doer(req)
 }
 
 // Generate api url.
-func apiUrl(api string) string {
-	return fmt.Sprintf("%s/%s?v=%s", BaseUrl, api, Version)
+func (c *Client) apiUrl(api string) string {
+	baseUrl, version := c.BaseUrl, c.Version
+	if len(baseUrl) <= 0 {
+		baseUrl = BaseUrl
+	}
+	if len(version) <= 0 {
+		version = Version
+	}
+
+	return fmt.Sprintf("%s/%s?v=%s", baseUrl, api, version)
+}
+
+// Send a http request and read its response body, with rate limiting and retry policy.
+//
+// (returns *APIError when the response is not successful)
+func (c *Client) send(api string, req *http.Request) (result []byte, err error) {
+	limiter := c.limiterFor(api)
+
+	for attempt := 1; ; attempt++ {
+		if err = limiter.Wait(req.Context()); err != nil {
+			break
+		}
+
+		if result, err = c.sendOnce(req); err == nil {
+			return result, nil
+		}
+
+		wait, retry := c.retryPolicy.backoff(req, attempt, err)
+		if !retry {
+			break
+		}
+		if l := c.log(); l != nil {
+			l.Warn("retrying", "method", req.Method, "url", req.URL.String(), "attempt", attempt, "wait", wait, "error", err)
+		}
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(req, attempt, wait, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return []byte{}, req.Context().Err()
+		case <-timer.C:
+		}
+
+		// rewind request body
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				break
+			}
+		}
+	}
+
+	return []byte{}, err
+}
+
+// Send a http request once and read its response body.
+func (c *Client) sendOnce(req *http.Request) (result []byte, err error) {
+	var resp *http.Response
+	if resp, err = c.do(req); err == nil {
+		defer resp.Body.Close()
+
+		if result, err = ioutil.ReadAll(resp.Body); err == nil {
+			c.logResponse(req, resp.StatusCode, result)
+
+			if err = checkResponse(resp.StatusCode, result); err == nil {
+				return result, nil
+			}
+
+			if apiErr, ok := err.(*APIError); ok {
+				apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			}
+		}
+	}
+
+	return []byte{}, err
 }
 
 // Get http header for authorization.
@@ -45,14 +204,12 @@ func (c *Client) authHeader() string {
 }
 
 // Do http get.
-func (c *Client) httpGet(api string, headers, params map[string]string) (result []byte, err error) {
-	url := apiUrl(api)
-	if c.Verbose {
-		log.Printf("[GET] requesting url: %s, headers: %+v, params: %+v\n", url, headers, params)
-	}
+func (c *Client) httpGet(ctx context.Context, api string, headers, params map[string]string) (result []byte, err error) {
+	url := c.apiUrl(api)
+	c.logRequest("GET", url, headers, params, nil)
 
 	var req *http.Request
-	if req, err = http.NewRequest("GET", url, nil); err == nil {
+	if req, err = http.NewRequestWithContext(ctx, "GET", url, nil); err == nil {
 		req.Header.Set("Authorization", c.authHeader())
 		for k, v := range headers { // additional http headers
 			req.Header.Set(k, v)
@@ -65,18 +222,8 @@ func (c *Client) httpGet(api string, headers, params map[string]string) (result
 		}
 		req.URL.RawQuery = query.Encode()
 
-		var resp *http.Response
-		client := &http.Client{}
-		if resp, err = client.Do(req); err == nil {
-			defer resp.Body.Close()
-
-			if result, err = ioutil.ReadAll(resp.Body); err == nil {
-				if c.Verbose {
-					log.Printf("response body: %s\n", string(result))
-				}
-
-				return result, err
-			}
+		if result, err = c.send(api, req); err == nil {
+			return result, nil
 		}
 	}
 
@@ -84,16 +231,14 @@ func (c *Client) httpGet(api string, headers, params map[string]string) (result
 }
 
 // Do http post, put, or delete. (json)
-func (c *Client) httpPostPutDelete(method, api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
-	url := apiUrl(api)
-	if c.Verbose {
-		log.Printf("[%s] requesting url: %s, headers: %+v, params: %+v, object: %+v\n", method, url, headers, params, object)
-	}
+func (c *Client) httpPostPutDelete(ctx context.Context, method, api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
+	url := c.apiUrl(api)
+	c.logRequest(method, url, headers, params, object)
 
 	var data []byte
 	if data, err = json.Marshal(object); err == nil {
 		var req *http.Request
-		if req, err = http.NewRequest(strings.ToUpper(method), url, bytes.NewBuffer(data)); err == nil {
+		if req, err = http.NewRequestWithContext(ctx, strings.ToUpper(method), url, bytes.NewBuffer(data)); err == nil {
 			req.Header.Set("Authorization", c.authHeader())
 			req.Header.Set("Content-Type", "application/json;charset=utf-8")
 			for k, v := range headers { // additional http headers
@@ -107,18 +252,8 @@ func (c *Client) httpPostPutDelete(method, api string, headers, params map[strin
 			}
 			req.URL.RawQuery = query.Encode()
 
-			var resp *http.Response
-			client := &http.Client{}
-			if resp, err = client.Do(req); err == nil {
-				defer resp.Body.Close()
-
-				if result, err = ioutil.ReadAll(resp.Body); err == nil {
-					if c.Verbose {
-						log.Printf("response body: %s\n", string(result))
-					}
-
-					return result, nil
-				}
+			if result, err = c.send(api, req); err == nil {
+				return result, nil
 			}
 		}
 	}
@@ -127,105 +262,112 @@ func (c *Client) httpPostPutDelete(method, api string, headers, params map[strin
 }
 
 // Do http post. (json)
-func (c *Client) httpPost(api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
-	return c.httpPostPutDelete("POST", api, headers, params, object)
+func (c *Client) httpPost(ctx context.Context, api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
+	return c.httpPostPutDelete(ctx, "POST", api, headers, params, object)
 }
 
 // Do http put. (json)
-func (c *Client) httpPut(api string, headers map[string]string, object interface{}) (result []byte, err error) {
-	return c.httpPostPutDelete("PUT", api, headers, nil, object)
+func (c *Client) httpPut(ctx context.Context, api string, headers map[string]string, object interface{}) (result []byte, err error) {
+	return c.httpPostPutDelete(ctx, "PUT", api, headers, nil, object)
 }
 
 // Do http delete.
-func (c *Client) httpDelete(api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
-	return c.httpPostPutDelete("DELETE", api, headers, params, object)
+func (c *Client) httpDelete(ctx context.Context, api string, headers, params map[string]string, object interface{}) (result []byte, err error) {
+	return c.httpPostPutDelete(ctx, "DELETE", api, headers, params, object)
 }
 
 // Do http post. (multipart)
-func (c *Client) httpPostMultipart(api string, headers map[string]string, params map[string]interface{}, files map[string]interface{}) (result []byte, err error) {
-	url := apiUrl(api)
-	if c.Verbose {
-		log.Printf("requesting url: %s, headers: %+v, params: %+v, files: %+v\n", url, headers, params, files)
-	}
-
-	var buffer bytes.Buffer
-	writer := multipart.NewWriter(&buffer)
-	var fw io.Writer
-
-	var req *http.Request
-
-	// write strings
+//
+// - files: filepath(string) or io.Reader
+//
+// (request body is streamed through a pipe, so the request cannot be retried)
+func (c *Client) httpPostMultipart(ctx context.Context, api string, headers map[string]string, params map[string]interface{}, files map[string]interface{}) (result []byte, err error) {
+	url := c.apiUrl(api)
+	c.logRequest("POST", url, headers, nil, params)
+
+	// marshal params
+	fields := map[string][]byte{}
 	for k, v := range params {
-		if fw, err = writer.CreateFormField(k); err != nil {
-			goto OnError
-		}
 		var data []byte
 		if data, err = json.Marshal(v); err != nil {
-			goto OnError
-		}
-		if _, err = fw.Write(data); err != nil {
-			goto OnError
+			return []byte{}, err
 		}
+		fields[k] = data
 	}
 
-	// write file
+	// open files
+	type formFile struct {
+		filename string
+		reader   io.Reader
+	}
+	formFiles := map[string]formFile{}
 	for k, v := range files {
-		var file *os.File = nil
-
 		switch t := v.(type) {
-		case *os.File:
-			file = v.(*os.File)
-			var stat os.FileInfo
-			if stat, err = file.Stat(); err != nil {
-				goto OnError
-			}
-			if fw, err = writer.CreateFormFile(k, stat.Name()); err != nil {
-				goto OnError
-			}
 		case string: // filepath
-			var filename string = v.(string)
-			if fw, err = writer.CreateFormFile(k, filename); err != nil {
-				goto OnError
-			}
-			if file, err = os.Open(filename); err != nil {
-				goto OnError
+			var file *os.File
+			if file, err = os.Open(t); err != nil {
+				return []byte{}, err
 			}
 			defer file.Close()
+			formFiles[k] = formFile{filename: filepath.Base(t), reader: file}
+		case *os.File:
+			formFiles[k] = formFile{filename: filepath.Base(t.Name()), reader: t}
+		case io.Reader:
+			formFiles[k] = formFile{filename: k, reader: t}
 		default:
 			return []byte{}, fmt.Errorf("type %T not supported", t)
 		}
-		if _, err = io.Copy(fw, file); err != nil {
-			goto OnError
-		}
 	}
 
-	// close writer
-	writer.Close()
+	pr, pw := io.Pipe()
+	defer pr.Close() // unblocks the writer below when the body was not fully consumed (eg. rate limited, canceled)
+	writer := multipart.NewWriter(pw)
+
+	// write multipart body in background
+	go func() {
+		var err error
+		defer func() {
+			if err == nil {
+				err = writer.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+
+		var fw io.Writer
 
-	if req, err = http.NewRequest("POST", url, &buffer); err == nil {
+		// write strings
+		for k, data := range fields {
+			if fw, err = writer.CreateFormField(k); err != nil {
+				return
+			}
+			if _, err = fw.Write(data); err != nil {
+				return
+			}
+		}
+
+		// write files
+		for k, f := range formFiles {
+			if fw, err = writer.CreateFormFile(k, f.filename); err != nil {
+				return
+			}
+			if _, err = io.Copy(fw, f.reader); err != nil {
+				return
+			}
+		}
+	}()
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, "POST", url, pr); err == nil {
 		req.Header.Set("Authorization", c.authHeader())
 		req.Header.Set("Content-Type", writer.FormDataContentType())
 		for k, v := range headers { // additional http headers
 			req.Header.Set(k, v)
 		}
 
-		var resp *http.Response
-		client := &http.Client{}
-		if resp, err = client.Do(req); err == nil {
-			defer resp.Body.Close()
-
-			var bytes []byte
-			if bytes, err = ioutil.ReadAll(resp.Body); err == nil {
-				if c.Verbose {
-					log.Printf("response: %s\n", string(bytes))
-				}
-
-				return bytes, nil
-			}
+		if result, err = c.send(api, req); err == nil {
+			return result, nil
 		}
 	}
 
-OnError:
-
 	return []byte{}, err
 }
@@ -0,0 +1,84 @@
+// Command dialogflow-sync syncs a Dialogflow agent to spec files.
+//
+//	$ dialogflow-sync -token=XXXX [-lang=en] [-prune] [-yes] SPEC_FILE_OR_DIR ...
+//
+// It prints the plan of changes first, and applies it only after confirmation.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/agent"
+)
+
+func main() {
+	token := flag.String("token", os.Getenv("DIALOGFLOW_TOKEN"), "developer access token (default: $DIALOGFLOW_TOKEN)")
+	lang := flag.String("lang", "", "language of the agent (overrides the one in spec files)")
+	prune := flag.Bool("prune", false, "delete intents and entities which are not in spec files")
+	yes := flag.Bool("yes", false, "apply without confirmation")
+	verbose := flag.Bool("verbose", false, "print verbose messages")
+	flag.Parse()
+
+	if len(*token) <= 0 || flag.NArg() <= 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s -token=TOKEN [options] SPEC_FILE_OR_DIR ...\n\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	desired, err := agent.LoadSpec(flag.Args()...)
+	if err != nil {
+		exit(err)
+	}
+	if len(*lang) > 0 {
+		desired.Language = df.LanguageTag(*lang)
+	}
+
+	client := df.NewClient(*token)
+	client.Verbose = *verbose
+
+	ctx := context.Background()
+
+	plan, err := agent.MakePlan(ctx, client, desired, agent.PlanOptions{Prune: *prune})
+	if err != nil {
+		exit(err)
+	}
+	plan.Print(os.Stdout)
+	if plan.Empty() {
+		return
+	}
+
+	if !*yes && !confirm("\nApply this plan? (yes/no): ") {
+		fmt.Println("Canceled.")
+		return
+	}
+
+	applied, err := plan.Apply(ctx, client)
+	fmt.Printf("Applied %d of %d action(s).\n", applied, len(plan.Actions))
+	if err != nil {
+		exit(err)
+	}
+}
+
+// Ask for confirmation on stdin.
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// Print error and exit.
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "*** error: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,84 @@
+package dialogflow
+
+import (
+	"strings"
+)
+
+// Contexts of query results.
+type Contexts []ContextObject
+
+// Check if this context has given name. (case-insensitive)
+func (c ContextObject) Is(name string) bool {
+	return strings.EqualFold(c.Name, name)
+}
+
+// Check if this context is active. (lifespan remains)
+func (c ContextObject) IsActive() bool {
+	return c.Lifespan > 0
+}
+
+// Get a parameter value of this context.
+func (c ContextObject) Param(name string) (value interface{}, exists bool) {
+	value, exists = c.Parameters[name]
+	return value, exists
+}
+
+// Get the original (unresolved) value of a parameter, from '<name>.original'.
+func (c ContextObject) OriginalParam(name string) (value interface{}, exists bool) {
+	return c.Param(name + ".original")
+}
+
+// Find a context with given name. (case-insensitive)
+func (cs Contexts) Find(name string) (ContextObject, bool) {
+	for _, c := range cs {
+		if c.Is(name) {
+			return c, true
+		}
+	}
+	return ContextObject{}, false
+}
+
+// Check if a context with given name exists and is active.
+func (cs Contexts) IsActive(name string) bool {
+	c, exists := cs.Find(name)
+	return exists && c.IsActive()
+}
+
+// Get the remaining lifespan of a context. (0 if not found)
+func (cs Contexts) Lifespan(name string) int {
+	if c, exists := cs.Find(name); exists {
+		return c.Lifespan
+	}
+	return 0
+}
+
+// Get a parameter value of a context.
+func (cs Contexts) Param(contextName, paramName string) (value interface{}, exists bool) {
+	if c, found := cs.Find(contextName); found {
+		return c.Param(paramName)
+	}
+	return nil, false
+}
+
+// Get the original value of a parameter of a context.
+func (cs Contexts) OriginalParam(contextName, paramName string) (value interface{}, exists bool) {
+	if c, found := cs.Find(contextName); found {
+		return c.OriginalParam(paramName)
+	}
+	return nil, false
+}
+
+// Get a context of query result with given name. (case-insensitive)
+func (r QueryResponse) Context(name string) (ContextObject, bool) {
+	return r.Result.Contexts.Find(name)
+}
+
+// Check if a context of query result is active.
+func (r QueryResponse) IsContextActive(name string) bool {
+	return r.Result.Contexts.IsActive(name)
+}
+
+// Get a parameter value of a context of query result.
+func (r QueryResponse) ContextParam(contextName, paramName string) (value interface{}, exists bool) {
+	return r.Result.Contexts.Param(contextName, paramName)
+}
@@ -3,14 +3,20 @@ package dialogflow
 // https://dialogflow.com/docs/reference/agent/contexts
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 // Get all contexts with given session id.
 func (c *Client) AllContexts(sid string) (result []ContextObject, err error) {
+	return c.AllContextsContext(context.Background(), sid)
+}
+
+// Get all contexts with given context and session id.
+func (c *Client) AllContextsContext(ctx context.Context, sid string) (result []ContextObject, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet("contexts", nil, map[string]string{"sessionId": sid}); err == nil {
+	if bytes, err = c.httpGet(ctx, "contexts", nil, map[string]string{"sessionId": sid}); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -21,8 +27,15 @@ func (c *Client) AllContexts(sid string) (result []ContextObject, err error) {
 
 // Get a context.
 func (c *Client) Context(sid, contextName string) (result ContextObject, err error) {
+	return c.ContextContext(context.Background(), sid, contextName)
+}
+
+// Get a context with given context.
+//
+// (ctx is a go context for the request, contextName is the name of a dialogflow context)
+func (c *Client) ContextContext(ctx context.Context, sid, contextName string) (result ContextObject, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet(fmt.Sprintf("contexts/%s", contextName), nil, map[string]string{"sessionId": sid}); err == nil {
+	if bytes, err = c.httpGet(ctx, fmt.Sprintf("contexts/%s", contextName), nil, map[string]string{"sessionId": sid}); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -33,8 +46,13 @@ func (c *Client) Context(sid, contextName string) (result ContextObject, err err
 
 // Create contexts.
 func (c *Client) CreateContexts(sid string, contexts []ContextObject) (result ContextResponseCreated, err error) {
+	return c.CreateContextsContext(context.Background(), sid, contexts)
+}
+
+// Create contexts with given context.
+func (c *Client) CreateContextsContext(ctx context.Context, sid string, contexts []ContextObject) (result ContextResponseCreated, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost("contexts", nil, map[string]string{"sessionId": sid}, contexts); err == nil {
+	if bytes, err = c.httpPost(ctx, "contexts", nil, map[string]string{"sessionId": sid}, contexts); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -45,8 +63,13 @@ func (c *Client) CreateContexts(sid string, contexts []ContextObject) (result Co
 
 // Delete all contexts.
 func (c *Client) DeleteContexts(sid string) (result ContextResponseDeleted, err error) {
+	return c.DeleteContextsContext(context.Background(), sid)
+}
+
+// Delete all contexts with given context.
+func (c *Client) DeleteContextsContext(ctx context.Context, sid string) (result ContextResponseDeleted, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete("contexts", nil, map[string]string{"sessionId": sid}, nil); err == nil {
+	if bytes, err = c.httpDelete(ctx, "contexts", nil, map[string]string{"sessionId": sid}, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -57,8 +80,13 @@ func (c *Client) DeleteContexts(sid string) (result ContextResponseDeleted, err
 
 // Delete a context.
 func (c *Client) DeleteContext(sid, contextName string) (result ApiResponse, err error) {
+	return c.DeleteContextContext(context.Background(), sid, contextName)
+}
+
+// Delete a context with given context.
+func (c *Client) DeleteContextContext(ctx context.Context, sid, contextName string) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete(fmt.Sprintf("contexts/%s", contextName), nil, map[string]string{"sessionId": sid}, nil); err == nil {
+	if bytes, err = c.httpDelete(ctx, fmt.Sprintf("contexts/%s", contextName), nil, map[string]string{"sessionId": sid}, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -0,0 +1,760 @@
+// Package dialogflowtest provides an in-process fake Dialogflow v1 server for testing.
+package dialogflowtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Function which returns a scripted response for a query.
+type QueryHandler func(query df.QueryRequest) df.QueryResponse
+
+// Fake Dialogflow v1 server backed by an in-memory agent.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	seq          int
+	intents      map[string]df.IntentObject             // key: intent id
+	entities     map[string]df.EntityObject             // key: entity id
+	contexts     map[string]map[string]df.ContextObject // key: session id, context name
+	userEntities map[string]df.UserEntityObject         // key: user entity name
+
+	queryHandler   QueryHandler
+	queryResponses map[string]df.QueryResponse // key: lowercased query text
+
+	injected []*injectedError
+	requests []string
+}
+
+type injectedError struct {
+	method    string
+	path      string
+	status    int
+	errorType df.ErrorType
+	remaining int // negative for permanent errors
+}
+
+// Start a new fake server.
+//
+// (close it with Close() after use)
+func NewServer() *Server {
+	s := &Server{
+		intents:        map[string]df.IntentObject{},
+		entities:       map[string]df.EntityObject{},
+		contexts:       map[string]map[string]df.ContextObject{},
+		userEntities:   map[string]df.UserEntityObject{},
+		queryResponses: map[string]df.QueryResponse{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// Get a new api client which points to this server.
+func (s *Server) NewClient(accessToken string, options ...df.ClientOption) *df.Client {
+	options = append([]df.ClientOption{
+		df.WithHttpClient(s.Server.Client()),
+		df.WithBaseUrl(s.Server.URL),
+	}, options...)
+
+	return df.NewClient(accessToken, options...)
+}
+
+// Set a handler for queries which have no scripted response.
+//
+// (the handler is called without holding the server's lock, so it can call methods of the server)
+func (s *Server) SetQueryHandler(handler QueryHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queryHandler = handler
+}
+
+// Set a scripted response for given query text. (case-insensitive)
+func (s *Server) SetQueryResponse(query string, response df.QueryResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queryResponses[strings.ToLower(query)] = response
+}
+
+// Make requests with given method and path (eg. "GET", "intents/some-id") fail with given http status.
+//
+// (empty method or path matches all, times < 0 means permanently)
+func (s *Server) InjectError(method, path string, status int, errorType df.ErrorType, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injected = append(s.injected, &injectedError{
+		method:    strings.ToUpper(method),
+		path:      strings.Trim(path, "/"),
+		status:    status,
+		errorType: errorType,
+		remaining: times,
+	})
+}
+
+// Remove all injected errors.
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.injected = nil
+}
+
+// Get all requests received so far. (eg. "GET intents")
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.requests...)
+}
+
+// Add an intent to the agent, returns its id.
+func (s *Server) AddIntent(intent df.IntentObject) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent.Id = s.newId()
+	s.intents[intent.Id] = intent
+
+	return intent.Id
+}
+
+// Get all intents of the agent.
+func (s *Server) Intents() []df.IntentObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intents := []df.IntentObject{}
+	for _, id := range s.sortedIntentIds() {
+		intents = append(intents, s.intents[id])
+	}
+
+	return intents
+}
+
+// Add an entity to the agent, returns its id.
+func (s *Server) AddEntity(entity df.EntityObject) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity.Id = s.newId()
+	s.entities[entity.Id] = entity
+
+	return entity.Id
+}
+
+// Get all entities of the agent.
+func (s *Server) Entities() []df.EntityObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entities := []df.EntityObject{}
+	for _, id := range s.sortedEntityIds() {
+		entities = append(entities, s.entities[id])
+	}
+
+	return entities
+}
+
+// Get active contexts of a session.
+func (s *Server) Contexts(sessionId string) []df.ContextObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessionContexts(sessionId)
+}
+
+// Get a user entity with given name.
+func (s *Server) UserEntity(name string) (entity df.UserEntityObject, exists bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity, exists = s.userEntities[name]
+	return entity, exists
+}
+
+// Generate a new id.
+func (s *Server) newId() string {
+	s.seq++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", s.seq)
+}
+
+func (s *Server) sortedIntentIds() []string {
+	ids := []string{}
+	for id := range s.intents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *Server) sortedEntityIds() []string {
+	ids := []string{}
+	for id := range s.entities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Find an entity with given id or name.
+func (s *Server) findEntity(eidOrName string) (df.EntityObject, bool) {
+	if entity, exists := s.entities[eidOrName]; exists {
+		return entity, true
+	}
+	for _, id := range s.sortedEntityIds() {
+		if s.entities[id].Name == eidOrName {
+			return s.entities[id], true
+		}
+	}
+	return df.EntityObject{}, false
+}
+
+func (s *Server) sessionContexts(sessionId string) []df.ContextObject {
+	contexts := []df.ContextObject{}
+	names := []string{}
+	for name := range s.contexts[sessionId] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		contexts = append(contexts, s.contexts[sessionId][name])
+	}
+	return contexts
+}
+
+func (s *Server) setContexts(sessionId string, contexts []df.ContextObject) (names []string) {
+	if _, exists := s.contexts[sessionId]; !exists {
+		s.contexts[sessionId] = map[string]df.ContextObject{}
+	}
+
+	names = []string{}
+	for _, ctx := range contexts {
+		name := strings.ToLower(ctx.Name)
+		if ctx.Lifespan <= 0 {
+			ctx.Lifespan = 5 // default lifespan
+		}
+		ctx.Name = name
+		s.contexts[sessionId][name] = ctx
+		names = append(names, name)
+	}
+	return names
+}
+
+// Check injected errors for given request.
+func (s *Server) injectedErrorFor(method, path string) *injectedError {
+	for i, e := range s.injected {
+		if (e.method == "" || e.method == method) && (e.path == "" || e.path == path) {
+			if e.remaining > 0 {
+				e.remaining--
+				if e.remaining == 0 {
+					s.injected = append(s.injected[:i], s.injected[i+1:]...)
+				}
+			}
+			return e
+		}
+	}
+	return nil
+}
+
+// Write a json response.
+func writeJson(w http.ResponseWriter, status int, object interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(object)
+}
+
+// Write a status object.
+func writeStatus(w http.ResponseWriter, status int, errorType df.ErrorType, details string) {
+	writeJson(w, status, map[string]interface{}{
+		"status": df.StatusObject{
+			Code:         status,
+			ErrorType:    errorType,
+			ErrorDetails: details,
+		},
+	})
+}
+
+// Write an ApiResponse.
+func writeSuccess(w http.ResponseWriter, id string) {
+	writeJson(w, http.StatusOK, df.ApiResponse{
+		Id:     id,
+		Status: df.StatusObject{Code: http.StatusOK, ErrorType: df.Success},
+	})
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(r.URL.Path, "/")
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, path))
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeStatus(w, http.StatusUnauthorized, df.Unauthorized, "no access token")
+		return
+	}
+	if e := s.injectedErrorFor(r.Method, path); e != nil {
+		if e.status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		writeStatus(w, e.status, e.errorType, "injected error")
+		return
+	}
+
+	paths := strings.Split(path, "/")
+	switch paths[0] {
+	case "query":
+		s.serveQuery(w, r)
+	case "intents":
+		s.serveIntents(w, r, paths[1:])
+	case "entities":
+		s.serveEntities(w, r, paths[1:])
+	case "contexts":
+		s.serveContexts(w, r, paths[1:])
+	case "userEntities":
+		s.serveUserEntities(w, r, paths[1:])
+	default:
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such api: %s", path))
+	}
+}
+
+// Decode json request body.
+func decode(w http.ResponseWriter, r *http.Request, object interface{}) bool {
+	if bytes, err := ioutil.ReadAll(r.Body); err == nil {
+		if err = json.Unmarshal(bytes, object); err == nil {
+			return true
+		}
+		writeStatus(w, http.StatusBadRequest, df.BadRequest, err.Error())
+	} else {
+		writeStatus(w, http.StatusBadRequest, df.BadRequest, err.Error())
+	}
+	return false
+}
+
+func notAllowed(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusMethodNotAllowed, df.NotAllowed, fmt.Sprintf("method not allowed: %s", r.Method))
+}
+
+func (s *Server) serveQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		notAllowed(w, r)
+		return
+	}
+
+	var query df.QueryRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") { // voice query
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			writeStatus(w, http.StatusBadRequest, df.BadRequest, err.Error())
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("request")), &query); err != nil {
+			writeStatus(w, http.StatusBadRequest, df.BadRequest, err.Error())
+			return
+		}
+	} else if !decode(w, r, &query) {
+		return
+	}
+	if len(query.SessionId) <= 0 {
+		writeStatus(w, http.StatusBadRequest, df.BadRequest, "sessionId is missing")
+		return
+	}
+
+	// update contexts of the session
+	if query.ResetContexts {
+		delete(s.contexts, query.SessionId)
+	}
+	for name, ctx := range s.contexts[query.SessionId] {
+		if ctx.Lifespan--; ctx.Lifespan <= 0 {
+			delete(s.contexts[query.SessionId], name)
+		} else {
+			s.contexts[query.SessionId][name] = ctx
+		}
+	}
+	s.setContexts(query.SessionId, query.Contexts)
+
+	text := strings.Join(query.Query, " ")
+
+	var response df.QueryResponse
+	if scripted, exists := s.queryResponses[strings.ToLower(text)]; exists {
+		response = scripted
+	} else if s.queryHandler != nil {
+		response = s.callQueryHandler(s.queryHandler, query)
+	} else {
+		response.Result.Source = "agent"
+		response.Result.Action = "input.unknown"
+		response.Result.Parameters = map[string]interface{}{}
+		response.Result.Metadata.IntentName = "Default Fallback Intent"
+	}
+
+	if response.Result.Contexts != nil {
+		s.setContexts(query.SessionId, response.Result.Contexts)
+	}
+	response.Result.Contexts = s.sessionContexts(query.SessionId)
+	if len(response.Result.ResolvedQuery) <= 0 {
+		response.Result.ResolvedQuery = text
+	}
+	if len(response.Id) <= 0 {
+		response.Id = s.newId()
+	}
+	if len(response.Language) <= 0 {
+		response.Language = query.Language
+	}
+	response.SessionId = query.SessionId
+	if len(response.Status.ErrorType) <= 0 {
+		response.Status = df.StatusObject{Code: http.StatusOK, ErrorType: df.Success}
+	}
+	response.ApiResponse.Status = response.Status
+
+	writeJson(w, http.StatusOK, response)
+}
+
+// Call a query handler without holding the lock, so that the handler can call into the server.
+//
+// (the caller must hold the lock, and it is held again on return, even when the handler panics)
+func (s *Server) callQueryHandler(handler QueryHandler, query df.QueryRequest) df.QueryResponse {
+	s.mu.Unlock()
+	defer s.mu.Lock()
+
+	return handler(query)
+}
+
+// Generate an intent summary.
+func summarizeIntent(intent df.IntentObject) df.Intent {
+	summary := df.Intent{
+		Id:             intent.Id,
+		Name:           intent.Name,
+		ContextIn:      intent.Contexts,
+		ContextOut:     []df.ContextOut{},
+		Actions:        []string{},
+		Parameters:     []df.IntentParameter{},
+		Priority:       intent.Priority,
+		FallbackIntent: intent.FallbackIntent,
+	}
+	for _, response := range intent.Responses {
+		if len(response.Action) > 0 {
+			summary.Actions = append(summary.Actions, response.Action)
+		}
+		for _, ctx := range response.AffectedContexts {
+			summary.ContextOut = append(summary.ContextOut, df.ContextOut{Name: ctx.Name, Lifespan: ctx.Lifespan})
+		}
+		for _, param := range response.Parameters {
+			summary.Parameters = append(summary.Parameters, df.IntentParameter{
+				Name:         param.Name,
+				Value:        param.Value,
+				DefaultValue: param.DefaultValue,
+				Required:     param.Required,
+				DataType:     param.DataType,
+				Prompts:      param.Prompts,
+			})
+		}
+	}
+	return summary
+}
+
+func (s *Server) serveIntents(w http.ResponseWriter, r *http.Request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case "GET":
+			intents := []df.Intent{}
+			for _, id := range s.sortedIntentIds() {
+				intents = append(intents, summarizeIntent(s.intents[id]))
+			}
+			writeJson(w, http.StatusOK, intents)
+		case "POST":
+			var intent df.IntentObject
+			if decode(w, r, &intent) {
+				intent.Id = s.newId()
+				s.intents[intent.Id] = intent
+				writeSuccess(w, intent.Id)
+			}
+		default:
+			notAllowed(w, r)
+		}
+		return
+	}
+
+	id := paths[0]
+	intent, exists := s.intents[id]
+	if !exists {
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such intent: %s", id))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, http.StatusOK, intent)
+	case "PUT":
+		var updated df.IntentObject // replaces the intent, as the api does
+		if decode(w, r, &updated) {
+			updated.Id = id
+			s.intents[id] = updated
+			writeSuccess(w, id)
+		}
+	case "DELETE":
+		delete(s.intents, id)
+		writeSuccess(w, id)
+	default:
+		notAllowed(w, r)
+	}
+}
+
+// Generate an entity summary.
+func summarizeEntity(entity df.EntityObject) df.Entity {
+	values := []string{}
+	for _, entry := range entity.Entries {
+		values = append(values, entry.Value)
+	}
+	preview := strings.Join(values, ", ")
+	if len(preview) > 50 {
+		preview = preview[:50] + "..."
+	}
+
+	return df.Entity{
+		Id:      entity.Id,
+		Name:    entity.Name,
+		Count:   len(entity.Entries),
+		Preview: preview,
+	}
+}
+
+// Merge entries by their values.
+func mergeEntries(entries, with []df.EntityEntryObject) []df.EntityEntryObject {
+	merged := append([]df.EntityEntryObject{}, entries...)
+	for _, entry := range with {
+		replaced := false
+		for i := range merged {
+			if merged[i].Value == entry.Value {
+				merged[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
+
+func (s *Server) serveEntities(w http.ResponseWriter, r *http.Request, paths []string) {
+	if len(paths) == 0 {
+		switch r.Method {
+		case "GET":
+			entities := []df.Entity{}
+			for _, id := range s.sortedEntityIds() {
+				entities = append(entities, summarizeEntity(s.entities[id]))
+			}
+			writeJson(w, http.StatusOK, df.Entities{
+				Entities: entities,
+				Status:   df.StatusObject{Code: http.StatusOK, ErrorType: df.Success},
+			})
+		case "POST":
+			var entity df.EntityObject
+			if decode(w, r, &entity) {
+				if _, exists := s.findEntity(entity.Name); exists {
+					writeStatus(w, http.StatusConflict, df.Conflict, fmt.Sprintf("entity already exists: %s", entity.Name))
+					return
+				}
+				entity.Id = s.newId()
+				s.entities[entity.Id] = entity
+				writeSuccess(w, entity.Id)
+			}
+		case "PUT":
+			var entities []df.EntityObject
+			if decode(w, r, &entities) {
+				for _, entity := range entities {
+					if existing, exists := s.findEntity(entity.Name); exists {
+						entity.Id = existing.Id
+					} else {
+						entity.Id = s.newId()
+					}
+					s.entities[entity.Id] = entity
+				}
+				writeSuccess(w, "")
+			}
+		default:
+			notAllowed(w, r)
+		}
+		return
+	}
+
+	entity, exists := s.findEntity(paths[0])
+	if !exists {
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such entity: %s", paths[0]))
+		return
+	}
+
+	if len(paths) == 1 {
+		switch r.Method {
+		case "GET":
+			writeJson(w, http.StatusOK, entity)
+		case "PUT":
+			var updated df.EntityObject // replaces the entity, as the api does
+			if decode(w, r, &updated) {
+				updated.Id = entity.Id
+				s.entities[entity.Id] = updated
+				writeSuccess(w, entity.Id)
+			}
+		case "DELETE":
+			delete(s.entities, entity.Id)
+			writeSuccess(w, entity.Id)
+		default:
+			notAllowed(w, r)
+		}
+		return
+	}
+
+	if paths[1] != "entries" {
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such api: %s", strings.Join(paths, "/")))
+		return
+	}
+
+	switch r.Method {
+	case "POST", "PUT":
+		var entries []df.EntityEntryObject
+		if decode(w, r, &entries) {
+			entity.Entries = mergeEntries(entity.Entries, entries)
+			s.entities[entity.Id] = entity
+			writeSuccess(w, entity.Id)
+		}
+	case "DELETE":
+		var values []string
+		if decode(w, r, &values) {
+			entries := []df.EntityEntryObject{}
+			for _, entry := range entity.Entries {
+				deleted := false
+				for _, value := range values {
+					if entry.Value == value {
+						deleted = true
+						break
+					}
+				}
+				if !deleted {
+					entries = append(entries, entry)
+				}
+			}
+			entity.Entries = entries
+			s.entities[entity.Id] = entity
+			writeSuccess(w, entity.Id)
+		}
+	default:
+		notAllowed(w, r)
+	}
+}
+
+func (s *Server) serveContexts(w http.ResponseWriter, r *http.Request, paths []string) {
+	sessionId := r.URL.Query().Get("sessionId")
+	if len(sessionId) <= 0 {
+		writeStatus(w, http.StatusBadRequest, df.BadRequest, "sessionId is missing")
+		return
+	}
+
+	if len(paths) == 0 {
+		switch r.Method {
+		case "GET":
+			writeJson(w, http.StatusOK, s.sessionContexts(sessionId))
+		case "POST":
+			var contexts []df.ContextObject
+			if decode(w, r, &contexts) {
+				writeJson(w, http.StatusOK, df.ContextResponseCreated{
+					ApiResponse: df.ApiResponse{Status: df.StatusObject{Code: http.StatusOK, ErrorType: df.Success}},
+					Names:       s.setContexts(sessionId, contexts),
+				})
+			}
+		case "DELETE":
+			deleted := []string{}
+			for _, ctx := range s.sessionContexts(sessionId) {
+				deleted = append(deleted, ctx.Name)
+			}
+			delete(s.contexts, sessionId)
+			writeJson(w, http.StatusOK, df.ContextResponseDeleted{
+				ApiResponse: df.ApiResponse{Status: df.StatusObject{Code: http.StatusOK, ErrorType: df.Success}},
+				Deleted:     deleted,
+			})
+		default:
+			notAllowed(w, r)
+		}
+		return
+	}
+
+	name := strings.ToLower(paths[0])
+	ctx, exists := s.contexts[sessionId][name]
+	if !exists {
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such context: %s", name))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, http.StatusOK, ctx)
+	case "DELETE":
+		delete(s.contexts[sessionId], name)
+		writeSuccess(w, "")
+	default:
+		notAllowed(w, r)
+	}
+}
+
+func (s *Server) serveUserEntities(w http.ResponseWriter, r *http.Request, paths []string) {
+	if len(paths) == 0 {
+		if r.Method != "POST" {
+			notAllowed(w, r)
+			return
+		}
+
+		var entities df.NewUserEntitiesObject
+		if decode(w, r, &entities) {
+			for _, entity := range entities.Entities {
+				if len(entity.SessionId) <= 0 {
+					entity.SessionId = entities.SessionId
+				}
+				s.userEntities[entity.Name] = entity
+			}
+			writeSuccess(w, "")
+		}
+		return
+	}
+
+	name := paths[0]
+	entity, exists := s.userEntities[name]
+	if !exists {
+		writeStatus(w, http.StatusNotFound, df.NotFound, fmt.Sprintf("no such user entity: %s", name))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJson(w, http.StatusOK, entity)
+	case "PUT":
+		var updated df.UserEntityObject // do not decode into the stored one, which shares its entries
+		if decode(w, r, &updated) {
+			if updated.Extend {
+				updated.Entries = mergeEntries(entity.Entries, updated.Entries)
+			}
+			if len(updated.SessionId) <= 0 {
+				updated.SessionId = entity.SessionId
+			}
+			updated.Name = name
+			s.userEntities[name] = updated
+			writeSuccess(w, "")
+		}
+	case "DELETE":
+		delete(s.userEntities, name)
+		writeSuccess(w, "")
+	default:
+		notAllowed(w, r)
+	}
+}
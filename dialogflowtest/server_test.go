@@ -0,0 +1,117 @@
+package dialogflowtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+func TestQueryHandlerCanCallServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.AddIntent(df.IntentObject{Name: "greet"})
+	s.SetQueryHandler(func(query df.QueryRequest) (response df.QueryResponse) {
+		// calls back into the server
+		s.AddIntent(df.IntentObject{Name: "added-by-handler"})
+		response.Result.Action = "intents"
+		response.Result.Parameters = df.Parameters{"count": len(s.Intents())}
+		s.Contexts(query.SessionId)
+		return response
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := s.NewClient("token").QueryTextContext(ctx, df.QueryRequest{
+		Query:     []string{"hello"},
+		SessionId: "session",
+		Language:  df.English,
+	})
+	if err != nil {
+		t.Fatalf("query failed: %s", err)
+	}
+	if count, _ := response.Result.Parameters.Number("count"); count != 2 {
+		t.Errorf("expected 2 intents seen by the handler, got %v", count)
+	}
+}
+
+func TestInjectError(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.InjectError("GET", "intents", 404, df.NotFound, 1)
+
+	client := s.NewClient("token")
+	if _, err := client.AllIntents(); err == nil {
+		t.Errorf("expected an injected error")
+	}
+	if _, err := client.AllIntents(); err != nil {
+		t.Errorf("expected no error after the injected one, got %s", err)
+	}
+}
+
+func TestExtendUserEntity(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	client := s.NewClient("token")
+	if _, err := client.CreateUserEntities("session", []df.UserEntityObject{{
+		Name: "fruit",
+		Entries: []df.EntityEntryObject{
+			{Value: "apple", Synonyms: []string{"apple"}},
+			{Value: "banana", Synonyms: []string{"banana"}},
+		},
+	}}); err != nil {
+		t.Fatalf("failed to create user entity: %s", err)
+	}
+	if _, err := client.UpdateUserEntity("fruit", df.UserEntityObject{
+		Name:    "fruit",
+		Extend:  true,
+		Entries: []df.EntityEntryObject{{Value: "cherry", Synonyms: []string{"cherry"}}},
+	}); err != nil {
+		t.Fatalf("failed to extend user entity: %s", err)
+	}
+
+	entity, _ := s.UserEntity("fruit")
+	values := []string{}
+	for _, entry := range entity.Entries {
+		values = append(values, entry.Value)
+	}
+	if strings.Join(values, ",") != "apple,banana,cherry" {
+		t.Errorf("expected apple,banana,cherry, got %v", values)
+	}
+	if entity.SessionId != "session" {
+		t.Errorf("expected session id to be kept, got '%s'", entity.SessionId)
+	}
+}
+
+func TestPutReplacesObjects(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	iid := s.AddIntent(df.IntentObject{
+		Name:      "order",
+		Responses: []df.IntentResponse{{Action: "order"}},
+		Priority:  100,
+	})
+	s.AddEntity(df.EntityObject{Name: "hero", IsEnum: true, Entries: []df.EntityEntryObject{{Value: "Roadhog"}}})
+
+	client := s.NewClient("token")
+	if _, err := client.UpdateIntent(iid, df.IntentObject{Name: "order"}); err != nil {
+		t.Fatalf("failed to update intent: %s", err)
+	}
+	if _, err := client.UpdateEntity("hero", df.EntityObject{Name: "hero"}); err != nil {
+		t.Fatalf("failed to update entity: %s", err)
+	}
+
+	if intents := s.Intents(); len(intents) != 1 || intents[0].Id != iid || len(intents[0].Responses) != 0 || intents[0].Priority != 0 {
+		t.Errorf("expected the intent to be replaced, got %+v", intents)
+	}
+	if entities := s.Entities(); len(entities) != 1 || entities[0].IsEnum || len(entities[0].Entries) != 0 {
+		t.Errorf("expected the entity to be replaced, got %+v", entities)
+	}
+}
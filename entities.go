@@ -3,14 +3,20 @@ package dialogflow
 // https://dialogflow.com/docs/reference/agent/entities
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 // Get all entities.
 func (c *Client) AllEntities() (result Entities, err error) {
+	return c.AllEntitiesContext(context.Background())
+}
+
+// Get all entities with given context.
+func (c *Client) AllEntitiesContext(ctx context.Context) (result Entities, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet("entities", nil, nil); err == nil {
+	if bytes, err = c.httpGet(ctx, "entities", nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -21,8 +27,13 @@ func (c *Client) AllEntities() (result Entities, err error) {
 
 // Get an entitiy with given eid.
 func (c *Client) Entity(eidOrName string) (result EntityObject, err error) {
+	return c.EntityContext(context.Background(), eidOrName)
+}
+
+// Get an entitiy with given context and eid.
+func (c *Client) EntityContext(ctx context.Context, eidOrName string) (result EntityObject, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet(fmt.Sprintf("entities/%s", eidOrName), nil, nil); err == nil {
+	if bytes, err = c.httpGet(ctx, fmt.Sprintf("entities/%s", eidOrName), nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -35,8 +46,15 @@ func (c *Client) Entity(eidOrName string) (result EntityObject, err error) {
 //
 // (do not fill Id, IsEnum, AutomatedExpansion value in EntityObject)
 func (c *Client) CreateEntity(entity EntityObject) (result ApiResponse, err error) {
+	return c.CreateEntityContext(context.Background(), entity)
+}
+
+// Create a new entity with given context.
+//
+// (do not fill Id, IsEnum, AutomatedExpansion value in EntityObject)
+func (c *Client) CreateEntityContext(ctx context.Context, entity EntityObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost("entities", nil, nil, entity); err == nil {
+	if bytes, err = c.httpPost(ctx, "entities", nil, nil, entity); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -47,8 +65,13 @@ func (c *Client) CreateEntity(entity EntityObject) (result ApiResponse, err erro
 
 // Add entires to an entity.
 func (c *Client) AddEntityEntries(eidOrName string, entries []EntityEntryObject) (result ApiResponse, err error) {
+	return c.AddEntityEntriesContext(context.Background(), eidOrName, entries)
+}
+
+// Add entires to an entity with given context.
+func (c *Client) AddEntityEntriesContext(ctx context.Context, eidOrName string, entries []EntityEntryObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost(fmt.Sprintf("entities/%s/entries", eidOrName), nil, nil, entries); err == nil {
+	if bytes, err = c.httpPost(ctx, fmt.Sprintf("entities/%s/entries", eidOrName), nil, nil, entries); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -61,8 +84,15 @@ func (c *Client) AddEntityEntries(eidOrName string, entries []EntityEntryObject)
 //
 // (do not fill Id, IsEnum, AutomatedExpansion value in EntityObject)
 func (c *Client) CreateOrUpdateEntities(entities []EntityObject) (result ApiResponse, err error) {
+	return c.CreateOrUpdateEntitiesContext(context.Background(), entities)
+}
+
+// Create/update entities with given context.
+//
+// (do not fill Id, IsEnum, AutomatedExpansion value in EntityObject)
+func (c *Client) CreateOrUpdateEntitiesContext(ctx context.Context, entities []EntityObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPut("entities", nil, entities); err == nil {
+	if bytes, err = c.httpPut(ctx, "entities", nil, entities); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -73,8 +103,13 @@ func (c *Client) CreateOrUpdateEntities(entities []EntityObject) (result ApiResp
 
 // Update an entity.
 func (c *Client) UpdateEntity(eidOrName string, entity EntityObject) (result ApiResponse, err error) {
+	return c.UpdateEntityContext(context.Background(), eidOrName, entity)
+}
+
+// Update an entity with given context.
+func (c *Client) UpdateEntityContext(ctx context.Context, eidOrName string, entity EntityObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPut(fmt.Sprintf("entities/%s", eidOrName), nil, entity); err == nil {
+	if bytes, err = c.httpPut(ctx, fmt.Sprintf("entities/%s", eidOrName), nil, entity); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -85,8 +120,13 @@ func (c *Client) UpdateEntity(eidOrName string, entity EntityObject) (result Api
 
 // Update entries of an entity.
 func (c *Client) UpdateEntityEntries(eidOrName string, entries []EntityEntryObject) (result ApiResponse, err error) {
+	return c.UpdateEntityEntriesContext(context.Background(), eidOrName, entries)
+}
+
+// Update entries of an entity with given context.
+func (c *Client) UpdateEntityEntriesContext(ctx context.Context, eidOrName string, entries []EntityEntryObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPut(fmt.Sprintf("entities/%s/entries", eidOrName), nil, entries); err == nil {
+	if bytes, err = c.httpPut(ctx, fmt.Sprintf("entities/%s/entries", eidOrName), nil, entries); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -97,8 +137,13 @@ func (c *Client) UpdateEntityEntries(eidOrName string, entries []EntityEntryObje
 
 // Delete an entity.
 func (c *Client) DeleteEntity(eidOrName string) (result ApiResponse, err error) {
+	return c.DeleteEntityContext(context.Background(), eidOrName)
+}
+
+// Delete an entity with given context.
+func (c *Client) DeleteEntityContext(ctx context.Context, eidOrName string) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete(fmt.Sprintf("entities/%s", eidOrName), nil, nil, nil); err == nil {
+	if bytes, err = c.httpDelete(ctx, fmt.Sprintf("entities/%s", eidOrName), nil, nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -109,8 +154,13 @@ func (c *Client) DeleteEntity(eidOrName string) (result ApiResponse, err error)
 
 // Delete entries of an entity.
 func (c *Client) DeleteEntityEntries(eidOrName string, entries []string) (result ApiResponse, err error) {
+	return c.DeleteEntityEntriesContext(context.Background(), eidOrName, entries)
+}
+
+// Delete entries of an entity with given context.
+func (c *Client) DeleteEntityEntriesContext(ctx context.Context, eidOrName string, entries []string) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete(fmt.Sprintf("entities/%s/entries", eidOrName), nil, nil, entries); err == nil {
+	if bytes, err = c.httpDelete(ctx, fmt.Sprintf("entities/%s/entries", eidOrName), nil, nil, entries); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
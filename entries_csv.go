@@ -0,0 +1,203 @@
+package dialogflow
+
+// Entity entries in Dialogflow console's CSV format:
+//
+//	"value","synonym1","synonym2",...
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Error returned when a CSV record has no entry value.
+var ErrEmptyEntryValue = errors.New("empty entry value")
+
+// Streaming reader of entity entries in CSV format.
+type EntriesReader struct {
+	r    *csv.Reader
+	line int
+}
+
+// Get a new entries reader.
+func NewEntriesReader(r io.Reader) *EntriesReader {
+	// skip UTF-8 BOM, which is often prepended by spreadsheet applications
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1 // number of synonyms varies
+	cr.TrimLeadingSpace = true
+
+	return &EntriesReader{r: cr}
+}
+
+// Read an entry.
+//
+// (returns io.EOF when there is no more entry, and the value itself is used as a synonym when no synonym is given)
+func (r *EntriesReader) Read() (entry EntityEntryObject, err error) {
+	for {
+		var record []string
+		if record, err = r.r.Read(); err != nil {
+			return EntityEntryObject{}, err
+		}
+		r.line++
+
+		// trim fields and drop empty synonyms
+		fields := []string{}
+		for i, field := range record {
+			field = strings.TrimSpace(field)
+			if len(field) > 0 || i == 0 {
+				fields = append(fields, field)
+			}
+		}
+
+		if len(fields) == 1 && len(fields[0]) <= 0 { // skip blank lines
+			continue
+		}
+		if len(fields[0]) <= 0 {
+			return EntityEntryObject{}, fmt.Errorf("record %d: %w", r.line, ErrEmptyEntryValue)
+		}
+
+		entry = EntityEntryObject{Value: fields[0], Synonyms: fields[1:]}
+		if len(entry.Synonyms) <= 0 {
+			entry.Synonyms = []string{entry.Value}
+		}
+		return entry, nil
+	}
+}
+
+// Read all entries.
+func (r *EntriesReader) ReadAll() (entries []EntityEntryObject, err error) {
+	entries = []EntityEntryObject{}
+	for {
+		var entry EntityEntryObject
+		if entry, err = r.Read(); err != nil {
+			if err == io.EOF {
+				return entries, nil
+			}
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Streaming writer of entity entries in CSV format.
+//
+// (all fields are quoted, as the console does)
+type EntriesWriter struct {
+	w *bufio.Writer
+}
+
+// Get a new entries writer.
+func NewEntriesWriter(w io.Writer) *EntriesWriter {
+	return &EntriesWriter{w: bufio.NewWriter(w)}
+}
+
+// Write an entry.
+func (w *EntriesWriter) Write(entry EntityEntryObject) (err error) {
+	if len(entry.Value) <= 0 {
+		return ErrEmptyEntryValue
+	}
+
+	fields := []string{quoteCsv(entry.Value)}
+	for _, synonym := range entry.Synonyms {
+		fields = append(fields, quoteCsv(synonym))
+	}
+
+	_, err = w.w.WriteString(strings.Join(fields, ",") + "\n")
+	return err
+}
+
+// Flush buffered entries to the underlying writer.
+func (w *EntriesWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// Quote a CSV field.
+func quoteCsv(field string) string {
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// Read entity entries from CSV.
+func ReadEntriesCsv(r io.Reader) ([]EntityEntryObject, error) {
+	return NewEntriesReader(r).ReadAll()
+}
+
+// Write entity entries as CSV.
+func WriteEntriesCsv(w io.Writer, entries []EntityEntryObject) (err error) {
+	writer := NewEntriesWriter(w)
+	for _, entry := range entries {
+		if err = writer.Write(entry); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// Upload entries in CSV to an entity.
+//
+// (entries with existing values are updated, and others are added)
+func (c *Client) UploadEntityEntriesCsv(eidOrName string, r io.Reader) (result ApiResponse, err error) {
+	return c.UploadEntityEntriesCsvContext(context.Background(), eidOrName, r)
+}
+
+// Upload entries in CSV to an entity with given context.
+//
+// (entries with existing values are updated, and others are added)
+func (c *Client) UploadEntityEntriesCsvContext(ctx context.Context, eidOrName string, r io.Reader) (result ApiResponse, err error) {
+	var entries []EntityEntryObject
+	if entries, err = ReadEntriesCsv(r); err != nil {
+		return ApiResponse{}, err
+	}
+
+	var entity EntityObject
+	if entity, err = c.EntityContext(ctx, eidOrName); err != nil {
+		return ApiResponse{}, err
+	}
+	existing := map[string]bool{}
+	for _, entry := range entity.Entries {
+		existing[entry.Value] = true
+	}
+
+	// split entries into existing and new ones (the latter wins on duplicated values)
+	updated, added := []EntityEntryObject{}, []EntityEntryObject{}
+	indices := map[string]int{}
+	for _, entry := range entries {
+		if existing[entry.Value] {
+			if i, exists := indices[entry.Value]; exists {
+				updated[i] = entry
+			} else {
+				indices[entry.Value] = len(updated)
+				updated = append(updated, entry)
+			}
+		} else {
+			if i, exists := indices[entry.Value]; exists {
+				added[i] = entry
+			} else {
+				indices[entry.Value] = len(added)
+				added = append(added, entry)
+			}
+		}
+	}
+
+	if len(updated) > 0 {
+		if result, err = c.UpdateEntityEntriesContext(ctx, eidOrName, updated); err != nil {
+			return ApiResponse{}, err
+		}
+	}
+	if len(added) > 0 {
+		if result, err = c.AddEntityEntriesContext(ctx, eidOrName, added); err != nil {
+			return ApiResponse{}, err
+		}
+	}
+
+	return result, nil
+}
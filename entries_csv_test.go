@@ -0,0 +1,104 @@
+package dialogflow_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestReadEntriesCsv(t *testing.T) {
+	csv := "\xEF\xBB\xBF" + // BOM
+		`"Roadhog","Roadhog","pig"` + "\n" +
+		"\n" +
+		`"Mercy", "angel",""` + "\n" +
+		"Genji\n" +
+		`"Say ""hi""","hello, there"` + "\r\n"
+
+	entries, err := df.ReadEntriesCsv(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("failed to read csv: %s", err)
+	}
+
+	expected := []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog", "pig"}},
+		{Value: "Mercy", Synonyms: []string{"angel"}},
+		{Value: "Genji", Synonyms: []string{"Genji"}},
+		{Value: `Say "hi"`, Synonyms: []string{"hello, there"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected %+v, got %+v", expected, entries)
+	}
+}
+
+func TestReadEntriesCsvErrors(t *testing.T) {
+	if _, err := df.ReadEntriesCsv(strings.NewReader("\"a\"\n,b\n")); !errors.Is(err, df.ErrEmptyEntryValue) {
+		t.Errorf("expected ErrEmptyEntryValue, got %v", err)
+	}
+	if _, err := df.ReadEntriesCsv(strings.NewReader("\"unterminated\n")); err == nil {
+		t.Errorf("expected an error for malformed csv")
+	}
+}
+
+func TestWriteEntriesCsv(t *testing.T) {
+	entries := []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog", "pig"}},
+		{Value: `Say "hi"`, Synonyms: []string{"hello, there", "line\nbreak"}},
+	}
+
+	var buf bytes.Buffer
+	if err := df.WriteEntriesCsv(&buf, entries); err != nil {
+		t.Fatalf("failed to write csv: %s", err)
+	}
+
+	expected := `"Roadhog","Roadhog","pig"` + "\n" + `"Say ""hi""","hello, there","line` + "\n" + `break"` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	// round trip
+	read, err := df.ReadEntriesCsv(&buf)
+	if err != nil {
+		t.Fatalf("failed to read written csv: %s", err)
+	}
+	if !reflect.DeepEqual(read, entries) {
+		t.Errorf("expected %+v after round trip, got %+v", entries, read)
+	}
+
+	if err = df.WriteEntriesCsv(&buf, []df.EntityEntryObject{{Value: ""}}); !errors.Is(err, df.ErrEmptyEntryValue) {
+		t.Errorf("expected ErrEmptyEntryValue, got %v", err)
+	}
+}
+
+func TestUploadEntityEntriesCsv(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.AddEntity(df.EntityObject{Name: "hero", Entries: []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog"}},
+		{Value: "Genji", Synonyms: []string{"Genji"}},
+	}})
+
+	csv := `"Roadhog","Roadhog","pig"` + "\n" + `"Mercy","angel"` + "\n"
+	if _, err := s.NewClient("token").UploadEntityEntriesCsv("hero", strings.NewReader(csv)); err != nil {
+		t.Fatalf("failed to upload csv: %s", err)
+	}
+
+	expected := []df.EntityEntryObject{
+		{Value: "Roadhog", Synonyms: []string{"Roadhog", "pig"}},
+		{Value: "Genji", Synonyms: []string{"Genji"}},
+		{Value: "Mercy", Synonyms: []string{"angel"}},
+	}
+	if entities := s.Entities(); len(entities) != 1 || !reflect.DeepEqual(entities[0].Entries, expected) {
+		t.Errorf("expected entries %+v, got %+v", expected, entities)
+	}
+
+	requests := strings.Join(s.Requests(), ", ")
+	if !strings.Contains(requests, "PUT entities/hero/entries") || !strings.Contains(requests, "POST entities/hero/entries") {
+		t.Errorf("expected both update and add of entries, got %s", requests)
+	}
+}
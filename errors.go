@@ -0,0 +1,117 @@
+package dialogflow
+
+// https://dialogflow.com/docs/reference/agent/#status_and_error_codes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for each ErrorType.
+//
+// (can be checked with errors.Is on errors returned from Client)
+var (
+	ErrDeprecated      = errors.New(string(Deprecated))
+	ErrBadRequest      = errors.New(string(BadRequest))
+	ErrUnauthorized    = errors.New(string(Unauthorized))
+	ErrNotFound        = errors.New(string(NotFound))
+	ErrNotAllowed      = errors.New(string(NotAllowed))
+	ErrNotAcceptable   = errors.New(string(NotAcceptable))
+	ErrConflict        = errors.New(string(Conflict))
+	ErrTooManyRequests = errors.New(string(TooManyRequests))
+)
+
+var errorsForTypes = map[ErrorType]error{
+	Deprecated:      ErrDeprecated,
+	BadRequest:      ErrBadRequest,
+	Unauthorized:    ErrUnauthorized,
+	NotFound:        ErrNotFound,
+	NotAllowed:      ErrNotAllowed,
+	NotAcceptable:   ErrNotAcceptable,
+	Conflict:        ErrConflict,
+	TooManyRequests: ErrTooManyRequests,
+}
+
+var errorTypesForHttpStatuses = map[int]ErrorType{
+	http.StatusBadRequest:       BadRequest,
+	http.StatusUnauthorized:     Unauthorized,
+	http.StatusNotFound:         NotFound,
+	http.StatusMethodNotAllowed: NotAllowed,
+	http.StatusNotAcceptable:    NotAcceptable,
+	http.StatusConflict:         Conflict,
+	http.StatusTooManyRequests:  TooManyRequests,
+}
+
+// Error returned when the api responded with a non-2xx http status or a non-success status object.
+type APIError struct {
+	HttpStatus   int       `json:"httpStatus"`
+	Code         int       `json:"code"`
+	ErrorType    ErrorType `json:"errorType"`
+	ErrorId      string    `json:"errorId,omitempty"`
+	ErrorDetails string    `json:"errorDetails,omitempty"`
+	Body         []byte    `json:"-"`
+
+	RetryAfter time.Duration `json:"-"` // from 'Retry-After' header, zero if not given
+}
+
+// Error message.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("api error (http status: %d, code: %d, type: %s", e.HttpStatus, e.Code, e.ErrorType)
+	if len(e.ErrorId) > 0 {
+		msg += fmt.Sprintf(", id: %s", e.ErrorId)
+	}
+	if len(e.ErrorDetails) > 0 {
+		msg += fmt.Sprintf(", details: %s", e.ErrorDetails)
+	}
+
+	return msg + ")"
+}
+
+// Unwrap to the sentinel error of its ErrorType.
+func (e *APIError) Unwrap() error {
+	if err, exists := errorsForTypes[e.ErrorType]; exists {
+		return err
+	}
+
+	return nil
+}
+
+// Check http status and status object of a response body.
+//
+// (2xx responses with non-error status codes, eg. 'deprecated' or 206 'partial_content' of webhook failures, are not failures)
+func checkResponse(httpStatus int, body []byte) error {
+	var response struct {
+		Status *StatusObject `json:"status"`
+	}
+	// NOTE: some responses (eg. all intents) are json arrays without status objects
+	_ = json.Unmarshal(body, &response)
+
+	success := httpStatus >= 200 && httpStatus < 300
+	if success && (response.Status == nil || response.Status.Code < 400) {
+		return nil
+	}
+
+	err := &APIError{
+		HttpStatus: httpStatus,
+		Code:       httpStatus,
+		Body:       body,
+	}
+	if response.Status != nil {
+		err.Code = response.Status.Code
+		err.ErrorType = response.Status.ErrorType
+		err.ErrorId = response.Status.ErrorId
+		err.ErrorDetails = response.Status.ErrorDetails
+	}
+	if err.ErrorType == "" || err.ErrorType == Success {
+		if typ, exists := errorTypesForHttpStatuses[err.Code]; exists {
+			err.ErrorType = typ
+		} else if typ, exists := errorTypesForHttpStatuses[httpStatus]; exists {
+			err.ErrorType = typ
+		}
+	}
+
+	return err
+}
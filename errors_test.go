@@ -0,0 +1,76 @@
+package dialogflow_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Get a client which responds with given http status and body for all requests.
+func clientRespondingWith(status int, body string) *df.Client {
+	return df.NewClient("token", df.WithMiddlewares(func(next df.Doer) df.Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}
+	}))
+}
+
+func TestDeprecatedIsNotFailure(t *testing.T) {
+	client := clientRespondingWith(200, `{"result":{"action":"order"},"status":{"code":200,"errorType":"deprecated"}}`)
+
+	response, err := client.QueryText(df.QueryRequest{Query: []string{"pizza"}, SessionId: "session"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if response.Result.Action != "order" {
+		t.Errorf("expected action 'order', got '%s'", response.Result.Action)
+	}
+}
+
+func TestPartialContentIsNotFailure(t *testing.T) {
+	client := clientRespondingWith(206, `{"result":{"action":"fallback"},"status":{"code":206,"errorType":"partial_content","errorDetails":"Webhook call failed."}}`)
+
+	response, err := client.QueryText(df.QueryRequest{Query: []string{"pizza"}, SessionId: "session"})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if response.Result.Action != "fallback" {
+		t.Errorf("expected action 'fallback', got '%s'", response.Result.Action)
+	}
+}
+
+func TestErrorStatusInSuccessfulResponse(t *testing.T) {
+	client := clientRespondingWith(200, `{"status":{"code":400,"errorType":"bad_request"}}`)
+
+	_, err := client.QueryText(df.QueryRequest{Query: []string{"pizza"}, SessionId: "session"})
+	if !errors.Is(err, df.ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestHttpErrorStatus(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.InjectError("GET", "intents", http.StatusNotFound, df.NotFound, 1)
+
+	_, err := s.NewClient("token").AllIntents()
+
+	var apiErr *df.APIError
+	if !errors.As(err, &apiErr) || apiErr.HttpStatus != http.StatusNotFound {
+		t.Fatalf("expected *APIError with http status 404, got %v", err)
+	}
+	if !errors.Is(err, df.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
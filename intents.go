@@ -3,14 +3,20 @@ package dialogflow
 // https://dialogflow.com/docs/reference/agent/intents
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 // Get all intents.
 func (c *Client) AllIntents() (result []Intent, err error) {
+	return c.AllIntentsContext(context.Background())
+}
+
+// Get all intents with given context.
+func (c *Client) AllIntentsContext(ctx context.Context) (result []Intent, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet("intents", nil, nil); err == nil {
+	if bytes, err = c.httpGet(ctx, "intents", nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -21,8 +27,13 @@ func (c *Client) AllIntents() (result []Intent, err error) {
 
 // Get an intent.
 func (c *Client) Intent(iid string) (result IntentObject, err error) {
+	return c.IntentContext(context.Background(), iid)
+}
+
+// Get an intent with given context.
+func (c *Client) IntentContext(ctx context.Context, iid string) (result IntentObject, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet(fmt.Sprintf("intents/%s", iid), nil, nil); err == nil {
+	if bytes, err = c.httpGet(ctx, fmt.Sprintf("intents/%s", iid), nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -35,8 +46,15 @@ func (c *Client) Intent(iid string) (result IntentObject, err error) {
 //
 // (do not fill Id in IntentObject)
 func (c *Client) CreateIntent(intent IntentObject) (result ApiResponse, err error) {
+	return c.CreateIntentContext(context.Background(), intent)
+}
+
+// Create a new intent with given context.
+//
+// (do not fill Id in IntentObject)
+func (c *Client) CreateIntentContext(ctx context.Context, intent IntentObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost("intents", nil, nil, intent); err == nil {
+	if bytes, err = c.httpPost(ctx, "intents", nil, nil, intent); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -47,8 +65,13 @@ func (c *Client) CreateIntent(intent IntentObject) (result ApiResponse, err erro
 
 // Update an intent.
 func (c *Client) UpdateIntent(iid string, intent IntentObject) (result ApiResponse, err error) {
+	return c.UpdateIntentContext(context.Background(), iid, intent)
+}
+
+// Update an intent with given context.
+func (c *Client) UpdateIntentContext(ctx context.Context, iid string, intent IntentObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPut(fmt.Sprintf("intents/%s", iid), nil, intent); err == nil {
+	if bytes, err = c.httpPut(ctx, fmt.Sprintf("intents/%s", iid), nil, intent); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -59,8 +82,13 @@ func (c *Client) UpdateIntent(iid string, intent IntentObject) (result ApiRespon
 
 // Delete an intent.
 func (c *Client) DeleteIntent(iid string) (result ApiResponse, err error) {
+	return c.DeleteIntentContext(context.Background(), iid)
+}
+
+// Delete an intent with given context.
+func (c *Client) DeleteIntentContext(ctx context.Context, iid string) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete(fmt.Sprintf("intents/%s", iid), nil, nil, nil); err == nil {
+	if bytes, err = c.httpDelete(ctx, fmt.Sprintf("intents/%s", iid), nil, nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -0,0 +1,155 @@
+package dialogflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Leveled logger with key/value fields.
+//
+// (*slog.Logger satisfies this interface)
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// Redaction settings for logging.
+//
+// (Authorization header is always redacted)
+type LogRedaction struct {
+	MaskQueryText  bool // mask query texts ('query', 'resolvedQuery')
+	MaskParameters bool // mask parameters ('parameters')
+}
+
+const redacted = "[REDACTED]"
+
+// Option for setting the logger.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Option for setting the redaction of logs.
+func WithLogRedaction(redaction LogRedaction) ClientOption {
+	return func(c *Client) {
+		c.redaction = redaction
+	}
+}
+
+// Logger which prints with the standard log package.
+//
+// (used when Client.Verbose is true and no logger is set)
+type stdLogger struct{}
+
+func (l stdLogger) print(level, msg string, args ...interface{}) {
+	fields := []string{}
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fields = append(fields, fmt.Sprintf("%v=%+v", args[i], args[i+1]))
+		} else {
+			fields = append(fields, fmt.Sprintf("%+v", args[i]))
+		}
+	}
+
+	log.Printf("[%s] %s %s\n", level, msg, strings.Join(fields, " "))
+}
+
+func (l stdLogger) Debug(msg string, args ...interface{}) { l.print("DEBUG", msg, args...) }
+func (l stdLogger) Info(msg string, args ...interface{})  { l.print("INFO", msg, args...) }
+func (l stdLogger) Warn(msg string, args ...interface{})  { l.print("WARN", msg, args...) }
+func (l stdLogger) Error(msg string, args ...interface{}) { l.print("ERROR", msg, args...) }
+
+// Get the logger of this client, nil if logging is disabled.
+func (c *Client) log() Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	if c.Verbose {
+		return stdLogger{}
+	}
+	return nil
+}
+
+// Log a request.
+func (c *Client) logRequest(method, url string, headers, params map[string]string, object interface{}) {
+	if l := c.log(); l != nil {
+		args := []interface{}{"method", method, "url", url, "headers", redactHeaders(headers), "params", params}
+		if object != nil {
+			var body interface{} = object
+			if data, err := json.Marshal(object); err == nil {
+				body = c.redactJson(data)
+			}
+			args = append(args, "body", body)
+		}
+
+		l.Debug("requesting", args...)
+	}
+}
+
+// Log a response.
+func (c *Client) logResponse(req *http.Request, status int, body []byte) {
+	if l := c.log(); l != nil {
+		l.Debug("response", "method", req.Method, "url", req.URL.String(), "status", status, "body", c.redactJson(body))
+	}
+}
+
+// Redact headers for logging.
+func redactHeaders(headers map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range headers {
+		if strings.EqualFold(k, "Authorization") {
+			v = redacted
+		}
+		result[k] = v
+	}
+
+	return result
+}
+
+// Redact json bytes for logging, returns a string.
+func (c *Client) redactJson(data []byte) string {
+	if !c.redaction.MaskQueryText && !c.redaction.MaskParameters {
+		return string(data)
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return redacted // not masking-able, so redact the whole
+	}
+	if data, err := json.Marshal(c.redactValue(obj)); err == nil {
+		return string(data)
+	}
+
+	return redacted
+}
+
+// Redact values of json objects recursively.
+func (c *Client) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			switch {
+			case c.redaction.MaskQueryText && (key == "query" || key == "resolvedQuery"):
+				v[key] = redacted
+			case c.redaction.MaskParameters && key == "parameters":
+				v[key] = redacted
+			default:
+				v[key] = c.redactValue(child)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = c.redactValue(child)
+		}
+		return v
+	}
+
+	return value
+}
@@ -0,0 +1,336 @@
+package dialogflow
+
+// https://dialogflow.com/docs/reference/agent/query#message_objects
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type MessageType int
+
+const (
+	TextResponseMessageObjectType  MessageType = 0
+	CardMessageObjectType          MessageType = 1
+	QuickRepliesMessageObjectType  MessageType = 2
+	ImageMessageObjectType         MessageType = 3
+	CustomPayloadMessageObjectType MessageType = 4
+	NoSuchObjectType               MessageType = -1
+)
+
+// Names of message types which are encoded as strings in json.
+var messageTypeNames = map[MessageType]string{}
+
+// Encode message type into json. (number or string)
+func (t MessageType) MarshalJSON() ([]byte, error) {
+	if name, exists := messageTypeNames[t]; exists {
+		return json.Marshal(name)
+	}
+	return json.Marshal(int(t))
+}
+
+// Decode message type from json. (number or string)
+//
+// (unknown names are decoded as NoSuchObjectType)
+func (t *MessageType) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*t = MessageType(number)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*t = NoSuchObjectType
+	for typ, n := range messageTypeNames {
+		if n == name {
+			*t = typ
+			break
+		}
+	}
+	return nil
+}
+
+// String representation of message type.
+func (t MessageType) String() string {
+	if name, exists := messageTypeNames[t]; exists {
+		return name
+	}
+	return fmt.Sprintf("%d", int(t))
+}
+
+type MessageObject struct {
+	Type     MessageType `json:"type"`
+	Platform string      `json:"platform,omitempty"`
+}
+
+// Get the common fields of a message object.
+func (o MessageObject) Header() MessageObject {
+	return o
+}
+
+// Typed content of a Message.
+//
+// (one of TextResponseMessageObject, CardMessageObject, QuickRepliesMessageObject,
+// ImageMessageObject, CustomPayloadMessageObject, platform-specific message objects,
+// or UnknownMessageObject)
+type MessageContent interface {
+	Header() MessageObject
+	messageType() MessageType
+}
+
+// Functions for decoding json into message objects, for each message type.
+var messageDecoders = map[MessageType]func(data []byte) (MessageContent, error){
+	TextResponseMessageObjectType: func(data []byte) (MessageContent, error) {
+		var o TextResponseMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	},
+	CardMessageObjectType: func(data []byte) (MessageContent, error) {
+		var o CardMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	},
+	QuickRepliesMessageObjectType: func(data []byte) (MessageContent, error) {
+		var o QuickRepliesMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	},
+	ImageMessageObjectType: func(data []byte) (MessageContent, error) {
+		var o ImageMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	},
+	CustomPayloadMessageObjectType: func(data []byte) (MessageContent, error) {
+		var o CustomPayloadMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	},
+}
+
+// Fulfillment message, which holds one of the typed message objects.
+type Message struct {
+	Content MessageContent
+}
+
+// Get a new message with given content.
+func NewMessage(content MessageContent) Message {
+	return Message{Content: content}
+}
+
+// Get the type of this message.
+func (m Message) Type() MessageType {
+	if m.Content == nil {
+		return NoSuchObjectType
+	}
+	return m.Content.messageType()
+}
+
+// Get the platform of this message. (empty for the default platform)
+func (m Message) Platform() string {
+	if m.Content == nil {
+		return ""
+	}
+	return m.Content.Header().Platform
+}
+
+// Decode json into a typed message object.
+func (m *Message) UnmarshalJSON(data []byte) (err error) {
+	var header struct {
+		Type     json.RawMessage `json:"type"`
+		Platform string          `json:"platform"`
+	}
+	if err = json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	typ := NoSuchObjectType
+	if len(header.Type) > 0 {
+		if err = json.Unmarshal(header.Type, &typ); err != nil {
+			typ = NoSuchObjectType
+		}
+	}
+
+	var content MessageContent
+	if decode, exists := messageDecoders[typ]; exists {
+		if content, err = decode(data); err != nil {
+			return fmt.Errorf("failed to decode message of type %s: %s", typ, err)
+		}
+	} else {
+		content = UnknownMessageObject{
+			MessageObject: MessageObject{
+				Type:     typ,
+				Platform: header.Platform,
+			},
+			Raw: append(json.RawMessage{}, data...),
+		}
+	}
+
+	m.Content = content
+	return nil
+}
+
+// Encode the typed message object into json.
+func (m Message) MarshalJSON() ([]byte, error) {
+	switch c := m.Content.(type) {
+	case nil:
+		return []byte("null"), nil
+	case UnknownMessageObject:
+		if len(c.Raw) > 0 {
+			return c.Raw, nil
+		}
+		return json.Marshal(c.MessageObject)
+	}
+
+	data, err := json.Marshal(m.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	// set 'type' from the content's type
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields["type"], err = json.Marshal(m.Content.messageType()); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+type TextResponseMessageObject struct {
+	MessageObject
+	Speech []string `json:"speech"`
+}
+
+func (o TextResponseMessageObject) messageType() MessageType {
+	return TextResponseMessageObjectType
+}
+
+// Decode json, where 'speech' can be a string or an array of strings.
+func (o *TextResponseMessageObject) UnmarshalJSON(data []byte) (err error) {
+	var obj struct {
+		MessageObject
+		Speech json.RawMessage `json:"speech"`
+	}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	o.MessageObject = obj.MessageObject
+	o.Speech = nil
+	if len(obj.Speech) > 0 && string(obj.Speech) != "null" {
+		var speech string
+		if err = json.Unmarshal(obj.Speech, &speech); err == nil {
+			o.Speech = []string{speech}
+		} else if err = json.Unmarshal(obj.Speech, &o.Speech); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// helper function for creating a TextResponseMessage
+func TextResponseMessage(platform string, speech []string) Message {
+	return NewMessage(TextResponseMessageObject{
+		MessageObject: MessageObject{
+			Type:     TextResponseMessageObjectType,
+			Platform: platform,
+		},
+		Speech: speech,
+	})
+}
+
+type CardMessageObject struct {
+	MessageObject
+	Title    string              `json:"title"`
+	Subtitle string              `json:"subtitle"`
+	ImageUrl string              `json:"imageUrl,omitempty"`
+	Buttons  []CardMessageButton `json:"buttons"`
+}
+
+func (o CardMessageObject) messageType() MessageType {
+	return CardMessageObjectType
+}
+
+type CardMessageButton struct {
+	Text     string `json:"text"`
+	Postback string `json:"postback"`
+}
+
+type QuickRepliesMessageObject struct {
+	MessageObject
+	Title   string   `json:"title"`
+	Replies []string `json:"replies"`
+}
+
+func (o QuickRepliesMessageObject) messageType() MessageType {
+	return QuickRepliesMessageObjectType
+}
+
+type ImageMessageObject struct {
+	MessageObject
+	ImageUrl string `json:"imageUrl"`
+}
+
+func (o ImageMessageObject) messageType() MessageType {
+	return ImageMessageObjectType
+}
+
+type CustomPayloadMessageObject struct {
+	MessageObject
+	Payload interface{} `json:"payload"`
+}
+
+func (o CustomPayloadMessageObject) messageType() MessageType {
+	return CustomPayloadMessageObjectType
+}
+
+// Message object of unknown type, which keeps its original json.
+type UnknownMessageObject struct {
+	MessageObject
+	Raw json.RawMessage `json:"-"`
+}
+
+func (o UnknownMessageObject) messageType() MessageType {
+	return o.Type
+}
+
+func (m Message) ToTextResponseMessage() TextResponseMessageObject {
+	if o, ok := m.Content.(TextResponseMessageObject); ok {
+		return o
+	}
+	return TextResponseMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
+
+func (m Message) ToCardMessage() CardMessageObject {
+	if o, ok := m.Content.(CardMessageObject); ok {
+		return o
+	}
+	return CardMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}, Buttons: []CardMessageButton{}}
+}
+
+func (m Message) ToQuickRepliesMessage() QuickRepliesMessageObject {
+	if o, ok := m.Content.(QuickRepliesMessageObject); ok {
+		return o
+	}
+	return QuickRepliesMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}, Replies: []string{}}
+}
+
+func (m Message) ToImageMessage() ImageMessageObject {
+	if o, ok := m.Content.(ImageMessageObject); ok {
+		return o
+	}
+	return ImageMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
+
+func (m Message) ToCustomPayloadMessage() CustomPayloadMessageObject {
+	if o, ok := m.Content.(CustomPayloadMessageObject); ok {
+		return o
+	}
+	return CustomPayloadMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
@@ -0,0 +1,275 @@
+package dialogflow
+
+// https://dialogflow.com/docs/reference/agent/message-objects#google_assistant_message_objects
+
+import (
+	"encoding/json"
+)
+
+// Platform name of Google Assistant (Actions on Google).
+const PlatformGoogle = "google"
+
+// Message types of Google Assistant.
+//
+// (encoded as strings in json)
+const (
+	SimpleResponseMessageObjectType  MessageType = 100
+	BasicCardMessageObjectType       MessageType = 101
+	ListCardMessageObjectType        MessageType = 102
+	CarouselCardMessageObjectType    MessageType = 103
+	SuggestionChipsMessageObjectType MessageType = 104
+	LinkOutChipMessageObjectType     MessageType = 105
+)
+
+func init() {
+	messageTypeNames[SimpleResponseMessageObjectType] = "simple_response"
+	messageTypeNames[BasicCardMessageObjectType] = "basic_card"
+	messageTypeNames[ListCardMessageObjectType] = "list_card"
+	messageTypeNames[CarouselCardMessageObjectType] = "carousel_card"
+	messageTypeNames[SuggestionChipsMessageObjectType] = "suggestion_chips"
+	messageTypeNames[LinkOutChipMessageObjectType] = "link_out_chip"
+
+	messageDecoders[SimpleResponseMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o SimpleResponseMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+	messageDecoders[BasicCardMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o BasicCardMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+	messageDecoders[ListCardMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o ListCardMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+	messageDecoders[CarouselCardMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o CarouselCardMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+	messageDecoders[SuggestionChipsMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o SuggestionChipsMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+	messageDecoders[LinkOutChipMessageObjectType] = func(data []byte) (MessageContent, error) {
+		var o LinkOutChipMessageObject
+		err := json.Unmarshal(data, &o)
+		return o, err
+	}
+}
+
+type SimpleResponseMessageObject struct {
+	MessageObject
+	TextToSpeech string `json:"textToSpeech,omitempty"`
+	Ssml         string `json:"ssml,omitempty"`
+	DisplayText  string `json:"displayText,omitempty"`
+}
+
+func (o SimpleResponseMessageObject) messageType() MessageType {
+	return SimpleResponseMessageObjectType
+}
+
+type BasicCardMessageObject struct {
+	MessageObject
+	Title         string            `json:"title,omitempty"`
+	Subtitle      string            `json:"subtitle,omitempty"`
+	FormattedText string            `json:"formattedText,omitempty"`
+	Image         *GoogleImage      `json:"image,omitempty"`
+	Buttons       []BasicCardButton `json:"buttons,omitempty"`
+}
+
+func (o BasicCardMessageObject) messageType() MessageType {
+	return BasicCardMessageObjectType
+}
+
+type GoogleImage struct {
+	Url               string `json:"url"`
+	AccessibilityText string `json:"accessibilityText,omitempty"`
+}
+
+type BasicCardButton struct {
+	Title         string        `json:"title"`
+	OpenUrlAction OpenUrlAction `json:"openUrlAction"`
+}
+
+type OpenUrlAction struct {
+	Url string `json:"url"`
+}
+
+type ListCardMessageObject struct {
+	MessageObject
+	Title string           `json:"title,omitempty"`
+	Items []SelectItemInfo `json:"items"`
+}
+
+func (o ListCardMessageObject) messageType() MessageType {
+	return ListCardMessageObjectType
+}
+
+type CarouselCardMessageObject struct {
+	MessageObject
+	Items []SelectItemInfo `json:"items"`
+}
+
+func (o CarouselCardMessageObject) messageType() MessageType {
+	return CarouselCardMessageObjectType
+}
+
+// Item of list or carousel cards.
+type SelectItemInfo struct {
+	OptionInfo  OptionInfo   `json:"optionInfo"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Image       *GoogleImage `json:"image,omitempty"`
+}
+
+type OptionInfo struct {
+	Key      string   `json:"key"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+type SuggestionChipsMessageObject struct {
+	MessageObject
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+func (o SuggestionChipsMessageObject) messageType() MessageType {
+	return SuggestionChipsMessageObjectType
+}
+
+type Suggestion struct {
+	Title string `json:"title"`
+}
+
+type LinkOutChipMessageObject struct {
+	MessageObject
+	DestinationName string `json:"destinationName"`
+	Url             string `json:"url"`
+}
+
+func (o LinkOutChipMessageObject) messageType() MessageType {
+	return LinkOutChipMessageObjectType
+}
+
+// helper function for creating a SimpleResponseMessage
+func SimpleResponseMessage(textToSpeech, displayText string) Message {
+	return NewMessage(SimpleResponseMessageObject{
+		MessageObject: MessageObject{
+			Type:     SimpleResponseMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		TextToSpeech: textToSpeech,
+		DisplayText:  displayText,
+	})
+}
+
+// helper function for creating a BasicCardMessage
+func BasicCardMessage(title, subtitle, formattedText string, image *GoogleImage, buttons []BasicCardButton) Message {
+	return NewMessage(BasicCardMessageObject{
+		MessageObject: MessageObject{
+			Type:     BasicCardMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		Title:         title,
+		Subtitle:      subtitle,
+		FormattedText: formattedText,
+		Image:         image,
+		Buttons:       buttons,
+	})
+}
+
+// helper function for creating a ListCardMessage
+func ListCardMessage(title string, items []SelectItemInfo) Message {
+	return NewMessage(ListCardMessageObject{
+		MessageObject: MessageObject{
+			Type:     ListCardMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		Title: title,
+		Items: items,
+	})
+}
+
+// helper function for creating a CarouselCardMessage
+func CarouselCardMessage(items []SelectItemInfo) Message {
+	return NewMessage(CarouselCardMessageObject{
+		MessageObject: MessageObject{
+			Type:     CarouselCardMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		Items: items,
+	})
+}
+
+// helper function for creating a SuggestionChipsMessage
+func SuggestionChipsMessage(titles []string) Message {
+	suggestions := []Suggestion{}
+	for _, title := range titles {
+		suggestions = append(suggestions, Suggestion{Title: title})
+	}
+
+	return NewMessage(SuggestionChipsMessageObject{
+		MessageObject: MessageObject{
+			Type:     SuggestionChipsMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		Suggestions: suggestions,
+	})
+}
+
+// helper function for creating a LinkOutChipMessage
+func LinkOutChipMessage(destinationName, url string) Message {
+	return NewMessage(LinkOutChipMessageObject{
+		MessageObject: MessageObject{
+			Type:     LinkOutChipMessageObjectType,
+			Platform: PlatformGoogle,
+		},
+		DestinationName: destinationName,
+		Url:             url,
+	})
+}
+
+func (m Message) ToSimpleResponseMessage() SimpleResponseMessageObject {
+	if o, ok := m.Content.(SimpleResponseMessageObject); ok {
+		return o
+	}
+	return SimpleResponseMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
+
+func (m Message) ToBasicCardMessage() BasicCardMessageObject {
+	if o, ok := m.Content.(BasicCardMessageObject); ok {
+		return o
+	}
+	return BasicCardMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
+
+func (m Message) ToListCardMessage() ListCardMessageObject {
+	if o, ok := m.Content.(ListCardMessageObject); ok {
+		return o
+	}
+	return ListCardMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}, Items: []SelectItemInfo{}}
+}
+
+func (m Message) ToCarouselCardMessage() CarouselCardMessageObject {
+	if o, ok := m.Content.(CarouselCardMessageObject); ok {
+		return o
+	}
+	return CarouselCardMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}, Items: []SelectItemInfo{}}
+}
+
+func (m Message) ToSuggestionChipsMessage() SuggestionChipsMessageObject {
+	if o, ok := m.Content.(SuggestionChipsMessageObject); ok {
+		return o
+	}
+	return SuggestionChipsMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}, Suggestions: []Suggestion{}}
+}
+
+func (m Message) ToLinkOutChipMessage() LinkOutChipMessageObject {
+	if o, ok := m.Content.(LinkOutChipMessageObject); ok {
+		return o
+	}
+	return LinkOutChipMessageObject{MessageObject: MessageObject{Type: NoSuchObjectType}}
+}
@@ -0,0 +1,142 @@
+package dialogflow_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Fulfillment of a query response, as sent by the api.
+const fulfillmentJson = `{
+  "speech": "Hi!",
+  "messages": [
+    {"type": 0, "speech": "Hi!"},
+    {"type": 0, "platform": "slack", "speech": ["Hello", "Hey"]},
+    {
+      "type": 1,
+      "platform": "facebook",
+      "title": "Pizza",
+      "subtitle": "Today's special",
+      "imageUrl": "https://example.com/pizza.png",
+      "buttons": [
+        {"text": "Order", "postback": "order pizza"},
+        {"text": "Menu", "postback": "https://example.com/menu"}
+      ]
+    },
+    {"type": 2, "title": "Size?", "replies": ["Small", "Large"]},
+    {"type": 3, "imageUrl": "https://example.com/image.png"},
+    {"type": 4, "payload": {"key": "value"}},
+    {"type": "simple_response", "platform": "google", "textToSpeech": "Hi there", "displayText": "Hi"},
+    {"type": 99, "something": "new"}
+  ]
+}`
+
+// Decode fulfillment from json.
+func decodeFulfillment(t *testing.T, data string) (fulfillment struct {
+	Speech   string       `json:"speech"`
+	Messages []df.Message `json:"messages"`
+}) {
+	if err := json.Unmarshal([]byte(data), &fulfillment); err != nil {
+		t.Fatalf("failed to decode fulfillment: %s", err)
+	}
+	return fulfillment
+}
+
+func TestDecodeMessages(t *testing.T) {
+	messages := decodeFulfillment(t, fulfillmentJson).Messages
+	if len(messages) != 8 {
+		t.Fatalf("expected 8 messages, got %d", len(messages))
+	}
+
+	// type 0 (which is the zero value)
+	if messages[0].Type() != df.TextResponseMessageObjectType {
+		t.Errorf("expected text response, got %s", messages[0].Type())
+	}
+	if speech := messages[0].ToTextResponseMessage().Speech; len(speech) != 1 || speech[0] != "Hi!" {
+		t.Errorf("unexpected speech: %v", speech)
+	}
+	if speech := messages[1].ToTextResponseMessage().Speech; len(speech) != 2 || messages[1].Platform() != "slack" {
+		t.Errorf("unexpected speech: %v (platform: %s)", speech, messages[1].Platform())
+	}
+
+	// card with buttons
+	card := messages[2].ToCardMessage()
+	if card.Type != df.CardMessageObjectType || card.Title != "Pizza" || card.ImageUrl != "https://example.com/pizza.png" {
+		t.Errorf("unexpected card: %+v", card)
+	}
+	if len(card.Buttons) != 2 || card.Buttons[0].Text != "Order" || card.Buttons[1].Postback != "https://example.com/menu" {
+		t.Errorf("unexpected card buttons: %+v", card.Buttons)
+	}
+
+	if replies := messages[3].ToQuickRepliesMessage(); len(replies.Replies) != 2 || replies.Title != "Size?" {
+		t.Errorf("unexpected quick replies: %+v", replies)
+	}
+	if image := messages[4].ToImageMessage(); image.ImageUrl != "https://example.com/image.png" {
+		t.Errorf("unexpected image: %+v", image)
+	}
+	if payload, ok := messages[5].ToCustomPayloadMessage().Payload.(map[string]interface{}); !ok || payload["key"] != "value" {
+		t.Errorf("unexpected payload: %+v", messages[5].ToCustomPayloadMessage().Payload)
+	}
+	if simple := messages[6].ToSimpleResponseMessage(); simple.TextToSpeech != "Hi there" || messages[6].Platform() != df.PlatformGoogle {
+		t.Errorf("unexpected simple response: %+v", simple)
+	}
+	if unknown, ok := messages[7].Content.(df.UnknownMessageObject); !ok || unknown.Type != 99 {
+		t.Errorf("expected unknown message of type 99, got %+v", messages[7].Content)
+	}
+
+	// wrong conversions
+	if messages[0].ToCardMessage().Type != df.NoSuchObjectType {
+		t.Errorf("expected NoSuchObjectType for a wrong conversion")
+	}
+}
+
+func TestEncodeMessages(t *testing.T) {
+	messages := decodeFulfillment(t, fulfillmentJson).Messages
+
+	data, err := json.Marshal(messages)
+	if err != nil {
+		t.Fatalf("failed to encode messages: %s", err)
+	}
+	if !strings.HasPrefix(string(data), `[{"speech":["Hi!"],"type":0}`) {
+		t.Errorf("expected type 0 to be encoded, got %s", data)
+	}
+	if !strings.Contains(string(data), `"type":"simple_response"`) || !strings.Contains(string(data), `"something":"new"`) {
+		t.Errorf("expected google and unknown messages to be kept, got %s", data)
+	}
+
+	// round trip
+	var decoded []df.Message
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode encoded messages: %s", err)
+	}
+	for i := range messages {
+		if decoded[i].Type() != messages[i].Type() {
+			t.Errorf("message %d: expected type %s, got %s", i, messages[i].Type(), decoded[i].Type())
+		}
+	}
+	if buttons := decoded[2].ToCardMessage().Buttons; len(buttons) != 2 || buttons[0].Postback != "order pizza" {
+		t.Errorf("unexpected card buttons after round trip: %+v", buttons)
+	}
+}
+
+func TestQueryResponseMessages(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	var response df.QueryResponse
+	if err := json.Unmarshal([]byte(`{"result":{"fulfillment":`+fulfillmentJson+`}}`), &response); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	s.SetQueryResponse("hello", response)
+
+	received, err := s.NewClient("token").QueryText(df.QueryRequest{Query: []string{"Hello"}, SessionId: "session"})
+	if err != nil {
+		t.Fatalf("query failed: %s", err)
+	}
+	if messages := received.Result.Fulfillment.Messages; len(messages) != 8 || messages[2].ToCardMessage().Buttons[0].Text != "Order" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
@@ -0,0 +1,288 @@
+package dialogflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned from parameter accessors.
+var (
+	ErrParameterMissing  = errors.New("parameter missing")
+	ErrParameterMismatch = errors.New("parameter type mismatch")
+)
+
+// Time layouts of parameters. (@sys.date-time, @sys.date, @sys.time)
+var parameterTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"15:04:05",
+}
+
+// Parameters of query results or contexts.
+type Parameters map[string]interface{}
+
+// Check if parameter with given name exists.
+func (p Parameters) Has(name string) bool {
+	_, exists := p[name]
+	return exists
+}
+
+// Get a parameter value.
+//
+// (empty strings, which are values of unfilled parameters, are treated as missing)
+func (p Parameters) value(name string) (interface{}, error) {
+	if v, exists := p[name]; exists && v != nil && v != "" {
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: '%s'", ErrParameterMissing, name)
+}
+
+func mismatch(name, expected string, v interface{}) error {
+	return fmt.Errorf("%w: '%s' is %T, not %s", ErrParameterMismatch, name, v, expected)
+}
+
+// Get a string parameter.
+func (p Parameters) String(name string) (string, error) {
+	v, err := p.value(name)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return "", mismatch(name, "string", v)
+}
+
+// Get a number parameter. (numeric strings are also accepted)
+func (p Parameters) Number(name string) (float64, error) {
+	v, err := p.value(name)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, mismatch(name, "number", v)
+}
+
+// Get a boolean parameter. ("true" and "false" strings are also accepted)
+func (p Parameters) Bool(name string) (bool, error) {
+	v, err := p.value(name)
+	if err != nil {
+		return false, err
+	}
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		if parsed, err := strconv.ParseBool(b); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, mismatch(name, "bool", v)
+}
+
+// Get a list parameter.
+func (p Parameters) List(name string) ([]interface{}, error) {
+	v, err := p.value(name)
+	if err != nil {
+		return nil, err
+	}
+	switch l := v.(type) {
+	case []interface{}:
+		return l, nil
+	case []string:
+		list := []interface{}{}
+		for _, s := range l {
+			list = append(list, s)
+		}
+		return list, nil
+	}
+	return nil, mismatch(name, "list", v)
+}
+
+// Get a list parameter of strings.
+func (p Parameters) StringList(name string) ([]string, error) {
+	list, err := p.List(name)
+	if err != nil {
+		return nil, err
+	}
+	strs := []string{}
+	for _, v := range list {
+		s, ok := v.(string)
+		if !ok {
+			return nil, mismatch(name, "list of strings", v)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// Get a nested object parameter. (eg. composite entities)
+func (p Parameters) Object(name string) (Parameters, error) {
+	v, err := p.value(name)
+	if err != nil {
+		return nil, err
+	}
+	switch o := v.(type) {
+	case map[string]interface{}:
+		return Parameters(o), nil
+	case Parameters:
+		return o, nil
+	}
+	return nil, mismatch(name, "object", v)
+}
+
+// Get a time parameter, from values of @sys.date-time, @sys.date, or @sys.time.
+//
+// (values without timezone are parsed in given location, or UTC if it is nil)
+func (p Parameters) Time(name string, loc *time.Location) (time.Time, error) {
+	s, err := p.String(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range parameterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: '%s' is not a time: %s", ErrParameterMismatch, name, s)
+}
+
+// Bind parameters into given struct pointer.
+//
+// Fields are matched with `dialogflow:"name"` tags (or field names),
+// and fields with `dialogflow:"name,required"` tags return ErrParameterMissing when missing.
+// (fields with `dialogflow:"-"` tags are skipped)
+func (p Parameters) Bind(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to a struct, not %T", dst)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		name, required := field.Name, false
+		if tag, exists := field.Tag.Lookup("dialogflow"); exists {
+			if tag == "-" {
+				continue
+			}
+			options := strings.Split(tag, ",")
+			if len(options[0]) > 0 {
+				name = options[0]
+			}
+			for _, option := range options[1:] {
+				if option == "required" {
+					required = true
+				}
+			}
+		}
+
+		if err := p.bindField(name, rv.Field(i)); err != nil {
+			if errors.Is(err, ErrParameterMissing) && !required {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Bind a parameter into a field.
+func (p Parameters) bindField(name string, field reflect.Value) error {
+	if field.Type() == timeType {
+		t, err := p.Time(name, nil)
+		if err == nil {
+			field.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, err := p.String(name)
+		if err == nil {
+			field.SetString(s)
+		}
+		return err
+	case reflect.Bool:
+		b, err := p.Bool(name)
+		if err == nil {
+			field.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := p.Number(name)
+		if err == nil {
+			if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 || field.OverflowInt(int64(n)) {
+				return fmt.Errorf("%w: '%s' is out of range of %s: %v", ErrParameterMismatch, name, field.Type(), n)
+			}
+			field.SetInt(int64(n))
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := p.Number(name)
+		if err == nil {
+			if n != math.Trunc(n) || n < 0 || n >= math.MaxUint64 || field.OverflowUint(uint64(n)) {
+				return fmt.Errorf("%w: '%s' is out of range of %s: %v", ErrParameterMismatch, name, field.Type(), n)
+			}
+			field.SetUint(uint64(n))
+		}
+		return err
+	case reflect.Float32, reflect.Float64:
+		n, err := p.Number(name)
+		if err == nil {
+			if field.OverflowFloat(n) {
+				return fmt.Errorf("%w: '%s' is out of range of %s: %v", ErrParameterMismatch, name, field.Type(), n)
+			}
+			field.SetFloat(n)
+		}
+		return err
+	}
+
+	// others: convert through json
+	v, err := p.value(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, field.Addr().Interface()); err != nil {
+		return fmt.Errorf("%w: '%s': %s", ErrParameterMismatch, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package dialogflow_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Parameters of a query result, as sent by the api.
+const parametersJson = `{
+  "name": "Roadhog",
+  "unfilled": "",
+  "count": 3,
+  "numeric": "2.5",
+  "negative": -1,
+  "fraction": 1.5,
+  "big": 300,
+  "flag": true,
+  "flagString": "false",
+  "heroes": ["Roadhog", "Mercy"],
+  "mixed": ["a", 1],
+  "address": {"city": "Seoul", "zip-code": "04524"},
+  "date": "2018-01-02",
+  "time": "13:04:05",
+  "dateTime": "2018-01-02T13:04:05Z",
+  "naiveDateTime": "2018-01-02T13:04:05",
+  "nothing": null
+}`
+
+// Decode parameters for tests.
+func testParameters(t *testing.T) df.Parameters {
+	var p df.Parameters
+	if err := json.Unmarshal([]byte(parametersJson), &p); err != nil {
+		t.Fatalf("failed to decode parameters: %s", err)
+	}
+	return p
+}
+
+func TestParameterAccessors(t *testing.T) {
+	p := testParameters(t)
+
+	if s, err := p.String("name"); err != nil || s != "Roadhog" {
+		t.Errorf("String: expected 'Roadhog', got '%s' (%v)", s, err)
+	}
+	if n, err := p.Number("count"); err != nil || n != 3 {
+		t.Errorf("Number: expected 3, got %v (%v)", n, err)
+	}
+	if n, err := p.Number("numeric"); err != nil || n != 2.5 {
+		t.Errorf("Number: expected 2.5 from string, got %v (%v)", n, err)
+	}
+	if b, err := p.Bool("flag"); err != nil || !b {
+		t.Errorf("Bool: expected true, got %v (%v)", b, err)
+	}
+	if b, err := p.Bool("flagString"); err != nil || b {
+		t.Errorf("Bool: expected false from string, got %v (%v)", b, err)
+	}
+	if l, err := p.StringList("heroes"); err != nil || !reflect.DeepEqual(l, []string{"Roadhog", "Mercy"}) {
+		t.Errorf("StringList: unexpected %v (%v)", l, err)
+	}
+	if _, err := p.StringList("mixed"); !errors.Is(err, df.ErrParameterMismatch) {
+		t.Errorf("StringList: expected ErrParameterMismatch, got %v", err)
+	}
+	if o, err := p.Object("address"); err != nil {
+		t.Errorf("Object: unexpected error: %s", err)
+	} else if city, _ := o.String("city"); city != "Seoul" {
+		t.Errorf("Object: expected city 'Seoul', got '%s'", city)
+	}
+
+	// missing or unfilled values
+	for _, name := range []string{"unfilled", "nothing", "no-such-parameter"} {
+		if _, err := p.String(name); !errors.Is(err, df.ErrParameterMissing) {
+			t.Errorf("String(%s): expected ErrParameterMissing, got %v", name, err)
+		}
+		if _, err := p.Number(name); !errors.Is(err, df.ErrParameterMissing) {
+			t.Errorf("Number(%s): expected ErrParameterMissing, got %v", name, err)
+		}
+		if _, err := p.Time(name, nil); !errors.Is(err, df.ErrParameterMissing) {
+			t.Errorf("Time(%s): expected ErrParameterMissing, got %v", name, err)
+		}
+	}
+	if !p.Has("unfilled") || p.Has("no-such-parameter") {
+		t.Errorf("Has: unexpected result")
+	}
+
+	// mismatches
+	if _, err := p.String("count"); !errors.Is(err, df.ErrParameterMismatch) {
+		t.Errorf("String: expected ErrParameterMismatch, got %v", err)
+	}
+	if _, err := p.Number("name"); !errors.Is(err, df.ErrParameterMismatch) {
+		t.Errorf("Number: expected ErrParameterMismatch, got %v", err)
+	}
+	if _, err := p.Time("name", nil); !errors.Is(err, df.ErrParameterMismatch) {
+		t.Errorf("Time: expected ErrParameterMismatch, got %v", err)
+	}
+}
+
+func TestParameterTime(t *testing.T) {
+	p := testParameters(t)
+	seoul := time.FixedZone("KST", 9*60*60)
+
+	for name, expected := range map[string]time.Time{
+		"date":          time.Date(2018, 1, 2, 0, 0, 0, 0, seoul),
+		"time":          time.Date(0, 1, 1, 13, 4, 5, 0, seoul),
+		"dateTime":      time.Date(2018, 1, 2, 13, 4, 5, 0, time.UTC),
+		"naiveDateTime": time.Date(2018, 1, 2, 13, 4, 5, 0, seoul),
+	} {
+		if tm, err := p.Time(name, seoul); err != nil || !tm.Equal(expected) {
+			t.Errorf("Time(%s): expected %s, got %s (%v)", name, expected, tm, err)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	p := testParameters(t)
+
+	var dst struct {
+		Name     string    `dialogflow:"name,required"`
+		Count    int       `dialogflow:"count"`
+		Numeric  float64   `dialogflow:"numeric"`
+		Flag     bool      `dialogflow:"flag"`
+		Heroes   []string  `dialogflow:"heroes"`
+		Date     time.Time `dialogflow:"date"`
+		Unfilled string    `dialogflow:"unfilled"`
+		Missing  int       `dialogflow:"no-such-parameter"`
+		Skipped  string    `dialogflow:"-"`
+		Address  struct {
+			City    string `json:"city"`
+			ZipCode string `json:"zip-code"`
+		} `dialogflow:"address"`
+	}
+	dst.Skipped = "kept"
+
+	if err := p.Bind(&dst); err != nil {
+		t.Fatalf("failed to bind: %s", err)
+	}
+	if dst.Name != "Roadhog" || dst.Count != 3 || dst.Numeric != 2.5 || !dst.Flag || len(dst.Heroes) != 2 || dst.Skipped != "kept" {
+		t.Errorf("unexpected bound values: %+v", dst)
+	}
+	if dst.Date.Year() != 2018 || dst.Address.City != "Seoul" || dst.Address.ZipCode != "04524" {
+		t.Errorf("unexpected bound values: %+v", dst)
+	}
+
+	if err := p.Bind(dst); err == nil {
+		t.Errorf("expected an error for non-pointer target")
+	}
+}
+
+func TestBindRequired(t *testing.T) {
+	p := testParameters(t)
+
+	var unfilled struct {
+		Value string `dialogflow:"unfilled,required"`
+	}
+	if err := p.Bind(&unfilled); !errors.Is(err, df.ErrParameterMissing) {
+		t.Errorf("expected ErrParameterMissing for unfilled required string, got %v", err)
+	}
+
+	var missing struct {
+		Value int `dialogflow:"no-such-parameter,required"`
+	}
+	if err := p.Bind(&missing); !errors.Is(err, df.ErrParameterMissing) {
+		t.Errorf("expected ErrParameterMissing for missing required number, got %v", err)
+	}
+
+	var mismatched struct {
+		Value int `dialogflow:"name"`
+	}
+	if err := p.Bind(&mismatched); !errors.Is(err, df.ErrParameterMismatch) {
+		t.Errorf("expected ErrParameterMismatch even for optional fields, got %v", err)
+	}
+}
+
+func TestBindNumberRanges(t *testing.T) {
+	p := testParameters(t)
+
+	for name, dst := range map[string]interface{}{
+		"negative into uint": &struct {
+			Value uint `dialogflow:"negative"`
+		}{},
+		"fraction into int": &struct {
+			Value int `dialogflow:"fraction"`
+		}{},
+		"fraction into uint": &struct {
+			Value uint `dialogflow:"fraction"`
+		}{},
+		"overflow of int8": &struct {
+			Value int8 `dialogflow:"big"`
+		}{},
+		"overflow of uint8": &struct {
+			Value uint8 `dialogflow:"big"`
+		}{},
+	} {
+		if err := p.Bind(dst); !errors.Is(err, df.ErrParameterMismatch) {
+			t.Errorf("%s: expected ErrParameterMismatch, got %v", name, err)
+		}
+	}
+
+	var ok struct {
+		Negative int8  `dialogflow:"negative"`
+		Big      int16 `dialogflow:"big"`
+		Count    uint8 `dialogflow:"count"`
+	}
+	if err := p.Bind(&ok); err != nil || ok.Negative != -1 || ok.Big != 300 || ok.Count != 3 {
+		t.Errorf("unexpected result: %+v (%v)", ok, err)
+	}
+}
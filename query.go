@@ -3,13 +3,28 @@ package dialogflow
 // https://dialogflow.com/docs/reference/agent/query
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"time"
 )
 
 // Query text.
 func (c *Client) QueryText(query QueryRequest) (result QueryResponse, err error) {
+	return c.QueryTextContext(context.Background(), query)
+}
+
+// Query text with given context.
+func (c *Client) QueryTextContext(ctx context.Context, query QueryRequest) (result QueryResponse, err error) {
+	defer c.record(query, time.Now(), &result, &err)
+
+	return c.queryText(ctx, query)
+}
+
+// Query text without recording transcripts.
+func (c *Client) queryText(ctx context.Context, query QueryRequest) (result QueryResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost("query", nil, nil, query); err == nil {
+	if bytes, err = c.httpPost(ctx, "query", nil, nil, query); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -18,22 +33,33 @@ func (c *Client) QueryText(query QueryRequest) (result QueryResponse, err error)
 	return QueryResponse{}, err
 }
 
-/*
 // Query voice in .wav(16000Hz, signed PCM, 16 bit, mono) format.
 //
-// - file: filepath(string) or opened file (*os.File)
+// - audio: wav data, which is validated before being sent
 //
 // NOTE: this api requires paid plan
-func (c *Client) QueryVoice(query QueryRequest, file interface{}) (result QueryResponse, err error) {
+func (c *Client) QueryVoice(query QueryRequest, audio io.Reader) (result QueryResponse, err error) {
+	return c.QueryVoiceContext(context.Background(), query, audio)
+}
+
+// Query voice with given context.
+func (c *Client) QueryVoiceContext(ctx context.Context, query QueryRequest, audio io.Reader) (result QueryResponse, err error) {
+	defer c.record(query, time.Now(), &result, &err)
+
+	if audio, err = validateWav(audio); err != nil {
+		return QueryResponse{}, err
+	}
+
 	var bytes []byte
 	if bytes, err = c.httpPostMultipart(
+		ctx,
 		"query",
 		nil,
 		map[string]interface{}{
 			"request": query,
 		},
 		map[string]interface{}{
-			"voiceData": file,
+			"voiceData": audio,
 		},
 	); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
@@ -43,4 +69,3 @@ func (c *Client) QueryVoice(query QueryRequest, file interface{}) (result QueryR
 
 	return QueryResponse{}, err
 }
-*/
@@ -0,0 +1,90 @@
+package dialogflow_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Generate wav data with given format and number of (silent) samples.
+func wavData(audioFormat, channels uint16, sampleRate uint32, bitsPerSample uint16, samples int) []byte {
+	blockAlign := channels * bitsPerSample / 8
+	dataSize := uint32(samples) * uint32(blockAlign)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36)+dataSize)
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, audioFormat)
+	binary.Write(&buf, binary.LittleEndian, channels)
+	binary.Write(&buf, binary.LittleEndian, sampleRate)
+	binary.Write(&buf, binary.LittleEndian, sampleRate*uint32(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, blockAlign)
+	binary.Write(&buf, binary.LittleEndian, bitsPerSample)
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+
+	return buf.Bytes()
+}
+
+// Get a valid wav data for voice queries.
+func validWav() []byte {
+	return wavData(1, df.VoiceChannels, df.VoiceSampleRate, df.VoiceBitsPerSample, 1600)
+}
+
+func TestQueryVoice(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.SetQueryHandler(func(query df.QueryRequest) (response df.QueryResponse) {
+		response.Result.Action = "voice"
+		return response
+	})
+
+	response, err := s.NewClient("token").QueryVoice(df.QueryRequest{SessionId: "session", Language: df.English}, bytes.NewReader(validWav()))
+	if err != nil {
+		t.Fatalf("voice query failed: %s", err)
+	}
+	if response.Result.Action != "voice" {
+		t.Errorf("expected action 'voice', got '%s'", response.Result.Action)
+	}
+}
+
+func TestQueryVoiceReleasesPipeWhenRateLimited(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	limiter := df.NewRateLimiter(0.001, 1, df.RateLimitFailFast)
+	client := s.NewClient("token", df.WithRateLimiters(limiter, nil))
+
+	query := df.QueryRequest{SessionId: "session", Language: df.English}
+	if _, err := client.QueryVoice(query, bytes.NewReader(validWav())); err != nil {
+		t.Fatalf("first voice query failed: %s", err)
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		// large enough not to fit in the pipe at once
+		if _, err := client.QueryVoice(query, bytes.NewReader(wavData(1, 1, 16000, 16, 160000))); !errors.Is(err, df.ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+	}
+
+	// wait for writer goroutines to finish
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before+5 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before+5 {
+		t.Errorf("goroutines leaked: %d before, %d after", before, after)
+	}
+}
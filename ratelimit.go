@@ -0,0 +1,113 @@
+package dialogflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error returned when a rate limiter with RateLimitFailFast has no token available.
+var ErrRateLimited = errors.New("rate limited")
+
+// Policy of a rate limiter when no token is available.
+type RateLimitPolicy int
+
+const (
+	RateLimitWait     RateLimitPolicy = iota // block until a token is available (or the context is done)
+	RateLimitFailFast                        // return ErrRateLimited immediately
+)
+
+// Token bucket rate limiter, safe for concurrent use.
+//
+// (can be shared by multiple clients which use the same access token)
+type RateLimiter struct {
+	mu sync.Mutex
+
+	rate   float64 // tokens per second
+	burst  float64
+	policy RateLimitPolicy
+
+	tokens float64
+	last   time.Time
+}
+
+// Get a new rate limiter which allows ratePerSecond requests per second with given burst size.
+func NewRateLimiter(ratePerSecond float64, burst int, policy RateLimitPolicy) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		policy: policy,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Option for setting rate limiters.
+//
+// queryLimiter is applied to queries, and agentLimiter to all other apis (intents, entities, contexts, user entities).
+// (nil for no limit)
+func WithRateLimiters(queryLimiter, agentLimiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.queryLimiter = queryLimiter
+		c.agentLimiter = agentLimiter
+	}
+}
+
+// Take a token, waiting for it according to the policy.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	if l.policy == RateLimitFailFast {
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+
+	// reserve a token and wait for it
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.tokens--
+	l.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// give back the reserved token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Get the rate limiter for given api.
+func (c *Client) limiterFor(api string) *RateLimiter {
+	if api == "query" || strings.HasPrefix(api, "query/") {
+		return c.queryLimiter
+	}
+	return c.agentLimiter
+}
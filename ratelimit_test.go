@@ -0,0 +1,69 @@
+package dialogflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestRateLimitFailFast(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	limiter := df.NewRateLimiter(0.001, 2, df.RateLimitFailFast)
+	client := s.NewClient("token", df.WithRateLimiters(nil, limiter))
+
+	for i := 0; i < 2; i++ { // burst
+		if _, err := client.AllIntents(); err != nil {
+			t.Fatalf("request %d in burst failed: %s", i, err)
+		}
+	}
+
+	start := time.Now()
+	if _, err := client.AllIntents(); !errors.Is(err, df.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected to fail immediately, took %s", elapsed)
+	}
+	if requests := s.Requests(); len(requests) != 2 {
+		t.Errorf("expected 2 requests to reach the server, got %v", requests)
+	}
+
+	// queries are not limited by the agent limiter
+	if _, err := client.QueryText(df.QueryRequest{Query: []string{"hello"}, SessionId: "session"}); err != nil {
+		t.Errorf("expected query not to be limited, got %s", err)
+	}
+}
+
+func TestRateLimitWaitHonorsContext(t *testing.T) {
+	limiter := df.NewRateLimiter(0.001, 1, df.RateLimitWait)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first token failed: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	limiter := df.NewRateLimiter(50, 1, df.RateLimitWait)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("wait failed: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected to wait for tokens, took only %s", elapsed)
+	}
+}
@@ -0,0 +1,98 @@
+package dialogflow
+
+import (
+	"strings"
+)
+
+// Platform names of fulfillment messages.
+//
+// (empty for the default platform)
+const (
+	PlatformDefault  = ""
+	PlatformFacebook = "facebook"
+	PlatformKik      = "kik"
+	PlatformLine     = "line"
+	PlatformSkype    = "skype"
+	PlatformSlack    = "slack"
+	PlatformSpark    = "spark"
+	PlatformTelegram = "telegram"
+	PlatformTwitter  = "twitter"
+	PlatformViber    = "viber"
+)
+
+// Get fulfillment messages for given platform.
+//
+// Returns messages of the platform if there is any, otherwise returns messages of the default platform.
+func (r QueryResponse) MessagesFor(platform string) []Message {
+	return SelectMessages(r.Result.Fulfillment.Messages, platform)
+}
+
+// Get speech text for given platform.
+//
+// (falls back to the fulfillment's speech when there is no speech in messages)
+func (r QueryResponse) SpeechFor(platform string) string {
+	if speech := FlattenSpeech(r.MessagesFor(platform)); len(speech) > 0 {
+		return speech
+	}
+	return r.Result.Fulfillment.Speech
+}
+
+// Select messages for given platform, with fallback to the default platform.
+func SelectMessages(messages []Message, platform string) []Message {
+	selected := []Message{}
+
+	if platform != PlatformDefault {
+		for _, message := range messages {
+			if message.Platform() == platform {
+				selected = append(selected, message)
+			}
+		}
+		if len(selected) > 0 {
+			return selected
+		}
+	}
+
+	for _, message := range messages {
+		if message.Platform() == PlatformDefault {
+			selected = append(selected, message)
+		}
+	}
+
+	return selected
+}
+
+// Flatten speech texts of messages into a string, separated by newlines.
+//
+// (first variant of text responses, display text or text to speech of simple responses,
+// and titles of cards and quick replies are used)
+func FlattenSpeech(messages []Message) string {
+	lines := []string{}
+	for _, message := range messages {
+		var line string
+
+		switch o := message.Content.(type) {
+		case TextResponseMessageObject:
+			if len(o.Speech) > 0 {
+				line = o.Speech[0]
+			}
+		case SimpleResponseMessageObject:
+			if len(o.DisplayText) > 0 {
+				line = o.DisplayText
+			} else {
+				line = o.TextToSpeech
+			}
+		case CardMessageObject:
+			line = o.Title
+		case QuickRepliesMessageObject:
+			line = o.Title
+		case BasicCardMessageObject:
+			line = o.Title
+		}
+
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package dialogflow
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy for failed requests.
+//
+// Requests are retried on 429(too many requests) responses,
+// and idempotent requests (GET, PUT, DELETE) are also retried on 5xx responses or transport errors.
+type RetryPolicy struct {
+	MaxAttempts    int           // maximum number of attempts including the first one
+	InitialBackoff time.Duration // backoff before the first retry
+	MaxBackoff     time.Duration // upper limit of backoff (not applied to 'Retry-After')
+	Multiplier     float64       // multiplier of backoff for each retry
+	Jitter         float64       // ratio of random jitter (0.0 ~ 1.0) subtracted from backoff
+
+	// called before each retry
+	OnRetry func(req *http.Request, attempt int, wait time.Duration, err error)
+}
+
+// Get a default retry policy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2.0,
+		Jitter:         0.2,
+	}
+}
+
+// Option for setting the retry policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// Check if given request is idempotent.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// Check if given request's body can be sent again.
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// Get the backoff before the next attempt, and whether to retry or not.
+func (p *RetryPolicy) backoff(req *http.Request, attempt int, err error) (wait time.Duration, retry bool) {
+	if p == nil || attempt >= p.MaxAttempts || req.Context().Err() != nil || !isRewindable(req) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HttpStatus == http.StatusTooManyRequests || apiErr.ErrorType == TooManyRequests {
+			retry = true
+		} else if apiErr.HttpStatus >= 500 {
+			retry = isIdempotent(req)
+		}
+	} else {
+		retry = isIdempotent(req) // transport errors
+	}
+	if !retry {
+		return 0, false
+	}
+
+	if apiErr != nil && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1.0 {
+		multiplier = 1.0
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1.0) * rand.Float64()
+	}
+
+	return time.Duration(backoff), true
+}
+
+// Parse value of 'Retry-After' header.
+//
+// (delay in seconds or http date)
+func parseRetryAfter(value string) time.Duration {
+	if len(value) <= 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,84 @@
+package dialogflow_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Get a retry policy with short backoffs for tests, which counts retries.
+func testRetryPolicy(retries *int) df.RetryPolicy {
+	policy := df.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	policy.OnRetry = func(req *http.Request, attempt int, wait time.Duration, err error) {
+		*retries++
+	}
+	return policy
+}
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.InjectError("POST", "query", http.StatusTooManyRequests, df.TooManyRequests, 2)
+	s.SetQueryHandler(func(query df.QueryRequest) (response df.QueryResponse) {
+		response.Result.Action = "retried"
+		return response
+	})
+
+	retries := 0
+	client := s.NewClient("token", df.WithRetryPolicy(testRetryPolicy(&retries)))
+
+	response, err := client.QueryText(df.QueryRequest{Query: []string{"hello"}, SessionId: "session"})
+	if err != nil {
+		t.Fatalf("expected success after retries, got %s", err)
+	}
+	if response.Result.Action != "retried" {
+		t.Errorf("expected action 'retried', got '%s'", response.Result.Action)
+	}
+	if retries != 2 {
+		t.Errorf("expected 2 retries, got %d", retries)
+	}
+	if requests := s.Requests(); len(requests) != 3 {
+		t.Errorf("expected 3 requests, got %v", requests)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.InjectError("GET", "intents", http.StatusTooManyRequests, df.TooManyRequests, -1)
+
+	retries := 0
+	client := s.NewClient("token", df.WithRetryPolicy(testRetryPolicy(&retries)))
+
+	if _, err := client.AllIntents(); !errors.Is(err, df.ErrTooManyRequests) {
+		t.Errorf("expected ErrTooManyRequests, got %v", err)
+	}
+	if max := df.DefaultRetryPolicy().MaxAttempts; retries != max-1 {
+		t.Errorf("expected %d retries, got %d", max-1, retries)
+	}
+}
+
+func TestNoRetryOfNonIdempotentServerError(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	s.InjectError("POST", "intents", http.StatusInternalServerError, "", 1)
+
+	retries := 0
+	client := s.NewClient("token", df.WithRetryPolicy(testRetryPolicy(&retries)))
+
+	if _, err := client.CreateIntent(df.IntentObject{Name: "greet"}); err == nil {
+		t.Errorf("expected an error")
+	}
+	if retries != 0 {
+		t.Errorf("expected no retry, got %d", retries)
+	}
+}
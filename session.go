@@ -0,0 +1,186 @@
+package dialogflow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// State of a session, which is persisted through SessionStore.
+type SessionState struct {
+	SessionId string          `json:"sessionId"`
+	Language  LanguageTag     `json:"lang"`
+	Timezone  string          `json:"timezone,omitempty"`
+	Contexts  []ContextObject `json:"contexts,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Session which remembers defaults of queries and tracks contexts of each turn.
+//
+// (safe for concurrent use, but queries of a session are serialized)
+type Session struct {
+	client *Client
+	store  SessionStore
+
+	mu      sync.Mutex
+	state   SessionState
+	pending bool // whether contexts should be sent with the next query (the server keeps them otherwise)
+}
+
+// Get a new session with given session id, language, and timezone.
+//
+// (store can be nil if the session does not need to be persisted)
+func (c *Client) NewSession(sessionId string, language LanguageTag, timezone string, store SessionStore) *Session {
+	return &Session{
+		client: c,
+		store:  store,
+		state: SessionState{
+			SessionId: sessionId,
+			Language:  language,
+			Timezone:  timezone,
+		},
+	}
+}
+
+// Open a session, resuming its state from the store if it exists.
+//
+// (language and timezone of the stored state are kept when given ones are empty)
+func (c *Client) OpenSession(ctx context.Context, store SessionStore, sessionId string, language LanguageTag, timezone string) (*Session, error) {
+	s := c.NewSession(sessionId, language, timezone, store)
+
+	if store != nil {
+		state, exists, err := store.Load(ctx, sessionId)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			if len(language) <= 0 {
+				s.state.Language = state.Language
+			}
+			if len(timezone) <= 0 {
+				s.state.Timezone = state.Timezone
+			}
+			s.state.Contexts = state.Contexts
+			s.state.UpdatedAt = state.UpdatedAt
+			s.pending = len(state.Contexts) > 0
+		}
+	}
+
+	return s, nil
+}
+
+// Get the session id.
+func (s *Session) Id() string {
+	return s.state.SessionId
+}
+
+// Get a copy of the current state.
+func (s *Session) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state
+	state.Contexts = append([]ContextObject{}, s.state.Contexts...)
+	return state
+}
+
+// Get contexts tracked from the last turn.
+func (s *Session) Contexts() Contexts {
+	return Contexts(s.State().Contexts)
+}
+
+// Replace tracked contexts, which will be sent with the next query.
+func (s *Session) SetContexts(ctx context.Context, contexts []ContextObject) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Contexts = append([]ContextObject{}, contexts...)
+	s.pending = true
+	return s.save(ctx)
+}
+
+// Query text in this session.
+func (s *Session) Query(text string) (QueryResponse, error) {
+	return s.QueryContext(context.Background(), text)
+}
+
+// Query text in this session with given context.
+func (s *Session) QueryContext(ctx context.Context, text string) (QueryResponse, error) {
+	return s.QueryRequest(ctx, QueryRequest{Query: []string{text}})
+}
+
+// Send a query request in this session.
+//
+// Empty session id, language, and timezone of the request are filled with the session's,
+// and active contexts of the response are tracked.
+//
+// (tracked contexts are sent only with the first query after resuming or setting them,
+// as re-sending them on every turn would reset their lifespans)
+func (s *Session) QueryRequest(ctx context.Context, query QueryRequest) (result QueryResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(query.SessionId) <= 0 {
+		query.SessionId = s.state.SessionId
+	}
+	if len(query.Language) <= 0 {
+		query.Language = s.state.Language
+	}
+	if len(query.Timezone) <= 0 {
+		query.Timezone = s.state.Timezone
+	}
+	if s.pending && len(query.Contexts) <= 0 && !query.ResetContexts {
+		query.Contexts = s.state.Contexts
+	}
+
+	if result, err = s.client.QueryTextContext(ctx, query); err != nil {
+		return result, err
+	}
+
+	contexts := []ContextObject{}
+	for _, c := range result.Result.Contexts {
+		if c.IsActive() {
+			contexts = append(contexts, c)
+		}
+	}
+	s.state.Contexts = contexts
+	s.pending = false
+
+	return result, s.save(ctx)
+}
+
+// Reset contexts of this session, both locally and on the server.
+func (s *Session) ResetContexts(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.client.DeleteContextsContext(ctx, s.state.SessionId); err != nil {
+		return err
+	}
+
+	s.state.Contexts = nil
+	s.pending = false
+	return s.save(ctx)
+}
+
+// Delete the state of this session from the store.
+func (s *Session) Delete(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Contexts = nil
+	s.pending = false
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Delete(ctx, s.state.SessionId)
+}
+
+// Save the state to the store. (must be called with lock held)
+func (s *Session) save(ctx context.Context) error {
+	s.state.UpdatedAt = time.Now()
+	if s.store == nil {
+		return nil
+	}
+	return s.store.Save(ctx, s.state)
+}
@@ -0,0 +1,163 @@
+package dialogflow
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Persistence of session states.
+type SessionStore interface {
+	// Load a session state, exists is false if there is no such state.
+	Load(ctx context.Context, sessionId string) (state SessionState, exists bool, err error)
+
+	// Save a session state.
+	Save(ctx context.Context, state SessionState) error
+
+	// Delete a session state.
+	Delete(ctx context.Context, sessionId string) error
+}
+
+// In-memory session store whose states expire after a ttl.
+type MemorySessionStore struct {
+	ttl time.Duration
+
+	mu     sync.Mutex
+	states map[string]SessionState
+}
+
+// Get a new in-memory session store. (ttl <= 0 for no expiration)
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:    ttl,
+		states: map[string]SessionState{},
+	}
+}
+
+func (s *MemorySessionStore) expired(state SessionState) bool {
+	return s.ttl > 0 && time.Since(state.UpdatedAt) > s.ttl
+}
+
+// Load a session state.
+func (s *MemorySessionStore) Load(ctx context.Context, sessionId string) (state SessionState, exists bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, exists = s.states[sessionId]; exists && s.expired(state) {
+		delete(s.states, sessionId)
+		return SessionState{}, false, nil
+	}
+	return state, exists, nil
+}
+
+// Save a session state.
+func (s *MemorySessionStore) Save(ctx context.Context, state SessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[state.SessionId] = state
+	return nil
+}
+
+// Delete a session state.
+func (s *MemorySessionStore) Delete(ctx context.Context, sessionId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, sessionId)
+	return nil
+}
+
+// Remove expired session states.
+func (s *MemorySessionStore) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, state := range s.states {
+		if s.expired(state) {
+			delete(s.states, id)
+		}
+	}
+}
+
+// File-backed session store which saves each state as a json file in a directory.
+type FileSessionStore struct {
+	dir string
+
+	mu sync.Mutex
+}
+
+// Get a new file-backed session store in given directory. (created if it does not exist)
+func NewFileSessionStore(dir string) (*FileSessionStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileSessionStore{dir: dir}, nil
+}
+
+// Get the file path for given session id.
+func (s *FileSessionStore) path(sessionId string) string {
+	return filepath.Join(s.dir, url.PathEscape(sessionId)+".json")
+}
+
+// Load a session state.
+func (s *FileSessionStore) Load(ctx context.Context, sessionId string) (state SessionState, exists bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(s.path(sessionId)); err != nil {
+		if os.IsNotExist(err) {
+			return SessionState{}, false, nil
+		}
+		return SessionState{}, false, err
+	}
+	if err = json.Unmarshal(bytes, &state); err != nil {
+		return SessionState{}, false, err
+	}
+	return state, true, nil
+}
+
+// Save a session state. (written atomically)
+func (s *FileSessionStore) Save(ctx context.Context, state SessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = ioutil.TempFile(s.dir, ".session-*"); err != nil {
+		return err
+	}
+	if _, err = file.Write(bytes); err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), s.path(state.SessionId))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// Delete a session state.
+func (s *FileSessionStore) Delete(ctx context.Context, sessionId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(sessionId)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package dialogflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+// Get a fake server which sets an 'ordering' context with lifespan 2 on "order".
+func orderingServer() *dialogflowtest.Server {
+	s := dialogflowtest.NewServer()
+
+	var response df.QueryResponse
+	response.Result.Contexts = []df.ContextObject{
+		{Name: "ordering", Lifespan: 2, Parameters: map[string]interface{}{"pizza": "pepperoni"}},
+	}
+	s.SetQueryResponse("order", response)
+
+	return s
+}
+
+func TestSessionContextsExpire(t *testing.T) {
+	s := orderingServer()
+	defer s.Close()
+
+	ctx := context.Background()
+	session := s.NewClient("token").NewSession("session", df.English, "", nil)
+
+	if _, err := session.QueryContext(ctx, "order"); err != nil {
+		t.Fatalf("failed to query: %s", err)
+	}
+	if c, exists := session.Contexts().Find("ordering"); !exists || c.Lifespan != 2 {
+		t.Fatalf("expected context 'ordering' with lifespan 2, got %+v", session.Contexts())
+	}
+
+	// lifespans of tracked contexts should not be reset by following turns
+	if _, err := session.QueryContext(ctx, "hello"); err != nil {
+		t.Fatalf("failed to query: %s", err)
+	}
+	if c, exists := session.Contexts().Find("ordering"); !exists || c.Lifespan != 1 {
+		t.Fatalf("expected context 'ordering' with lifespan 1, got %+v", session.Contexts())
+	}
+	if _, err := session.QueryContext(ctx, "hello"); err != nil {
+		t.Fatalf("failed to query: %s", err)
+	}
+	if len(session.Contexts()) != 0 || len(s.Contexts("session")) != 0 {
+		t.Errorf("expected contexts to be expired, got %+v / %+v", session.Contexts(), s.Contexts("session"))
+	}
+}
+
+func TestSessionResumeFromFileStore(t *testing.T) {
+	store, err := df.NewFileSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %s", err)
+	}
+	ctx := context.Background()
+
+	s := orderingServer()
+	session, err := s.NewClient("token").OpenSession(ctx, store, "session", df.English, "Asia/Seoul")
+	if err != nil {
+		t.Fatalf("failed to open session: %s", err)
+	}
+	if _, err := session.QueryContext(ctx, "order"); err != nil {
+		t.Fatalf("failed to query: %s", err)
+	}
+	s.Close()
+
+	// resume on a server which does not know the session
+	s = dialogflowtest.NewServer()
+	defer s.Close()
+
+	sent := [][]df.ContextObject{}
+	s.SetQueryHandler(func(query df.QueryRequest) (response df.QueryResponse) {
+		sent = append(sent, query.Contexts)
+		return response
+	})
+
+	resumed, err := s.NewClient("token").OpenSession(ctx, store, "session", "", "")
+	if err != nil {
+		t.Fatalf("failed to resume session: %s", err)
+	}
+	if state := resumed.State(); state.Language != df.English || state.Timezone != "Asia/Seoul" {
+		t.Errorf("expected language and timezone of the stored state, got %+v", state)
+	}
+	if c, exists := resumed.Contexts().Find("ordering"); !exists || c.Lifespan != 2 {
+		t.Fatalf("expected resumed context 'ordering', got %+v", resumed.Contexts())
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := resumed.QueryContext(ctx, "hello"); err != nil {
+			t.Fatalf("failed to query: %s", err)
+		}
+	}
+	if len(sent) != 2 || len(sent[0]) != 1 || sent[0][0].Name != "ordering" || len(sent[1]) != 0 {
+		t.Errorf("expected stored contexts to be sent only with the first query, got %+v", sent)
+	}
+	if c, exists := resumed.Contexts().Find("ordering"); !exists || c.Lifespan != 1 {
+		t.Errorf("expected context 'ordering' with lifespan 1, got %+v", resumed.Contexts())
+	}
+
+	// the store should be up to date
+	if state, exists, err := store.Load(ctx, "session"); err != nil || !exists || len(state.Contexts) != 1 || state.Contexts[0].Lifespan != 1 {
+		t.Errorf("unexpected stored state: %+v (%v, %v)", state, exists, err)
+	}
+}
+
+func TestMemorySessionStoreTtl(t *testing.T) {
+	store := df.NewMemorySessionStore(time.Minute)
+	ctx := context.Background()
+
+	if err := store.Save(ctx, df.SessionState{SessionId: "fresh", UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	if err := store.Save(ctx, df.SessionState{SessionId: "stale", UpdatedAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+
+	if _, exists, _ := store.Load(ctx, "fresh"); !exists {
+		t.Errorf("expected fresh state to exist")
+	}
+	if _, exists, _ := store.Load(ctx, "stale"); exists {
+		t.Errorf("expected stale state to be expired")
+	}
+
+	// expired states are not resumed
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+	store.Save(ctx, df.SessionState{
+		SessionId: "stale",
+		Contexts:  []df.ContextObject{{Name: "ordering", Lifespan: 2}},
+		UpdatedAt: time.Now().Add(-time.Hour),
+	})
+	if session, err := s.NewClient("token").OpenSession(ctx, store, "stale", df.English, ""); err != nil || len(session.Contexts()) != 0 {
+		t.Errorf("expected a new session, got %+v (%v)", session.State(), err)
+	}
+
+	// saving again refreshes the state, and no ttl means no expiration
+	forever := df.NewMemorySessionStore(0)
+	forever.Save(ctx, df.SessionState{SessionId: "stale", UpdatedAt: time.Now().Add(-24 * time.Hour)})
+	forever.Cleanup()
+	if _, exists, _ := forever.Load(ctx, "stale"); !exists {
+		t.Errorf("expected state without ttl not to expire")
+	}
+}
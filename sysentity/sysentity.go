@@ -0,0 +1,240 @@
+// Package sysentity converts values of Dialogflow system entities into Go types.
+package sysentity
+
+// https://dialogflow.com/docs/reference/system-entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Layouts of system entity values.
+const (
+	DateLayout     = "2006-01-02" // @sys.date
+	TimeLayout     = "15:04:05"   // @sys.time
+	DateTimeLayout = time.RFC3339 // @sys.date-time (with 'Z' or an offset)
+)
+
+// Period with start and end. (@sys.date-period, @sys.time-period, ...)
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Amount of money. (@sys.unit-currency)
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Amount with a unit. (@sys.unit-length, @sys.unit-weight, ...)
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// Units of @sys.duration.
+//
+// (months and years are approximated as 30 and 365 days)
+var durationUnits = map[string]time.Duration{
+	"s":   time.Second,
+	"sec": time.Second,
+	"min": time.Minute,
+	"h":   time.Hour,
+	"day": 24 * time.Hour,
+	"wk":  7 * 24 * time.Hour,
+	"mo":  30 * 24 * time.Hour,
+	"yr":  365 * 24 * time.Hour,
+}
+
+// Parser of date/time values in a timezone.
+type Parser struct {
+	Location *time.Location
+}
+
+// Get a new parser for given timezone name. (eg. "Asia/Seoul", UTC if empty)
+func NewParser(timezone string) (*Parser, error) {
+	loc := time.UTC
+	if len(timezone) > 0 {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Parser{Location: loc}, nil
+}
+
+// Get a new parser for the timezone of given query request.
+func ParserFor(query df.QueryRequest) (*Parser, error) {
+	return NewParser(query.Timezone)
+}
+
+func (p *Parser) location() *time.Location {
+	if p == nil || p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
+
+// Get a string value.
+func stringValue(value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("value is %T, not string", value)
+	}
+	if s = strings.TrimSpace(s); len(s) <= 0 {
+		return "", fmt.Errorf("value is empty")
+	}
+	return s, nil
+}
+
+// Parse a @sys.date value.
+func (p *Parser) Date(value interface{}) (time.Time, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(DateLayout, s, p.location())
+}
+
+// Parse a @sys.time value. (date part is 0000-01-01)
+func (p *Parser) Time(value interface{}) (time.Time, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(TimeLayout, s, p.location())
+}
+
+// Parse a @sys.time value on given date.
+func (p *Parser) TimeOn(value interface{}, date time.Time) (time.Time, error) {
+	t, err := p.Time(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date = date.In(p.location())
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), 0, p.location()), nil
+}
+
+// Parse a @sys.date-time value.
+//
+// (values with 'Z' or offsets are converted into the parser's location)
+func (p *Parser) DateTime(value interface{}) (time.Time, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, err := time.Parse(DateTimeLayout, s); err == nil {
+		return t.In(p.location()), nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05", s, p.location())
+}
+
+// Split a period value.
+func splitPeriod(value interface{}) (start, end string, err error) {
+	var s string
+	if s, err = stringValue(value); err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("not a period: %s", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// Parse a period value with given parse function.
+func (p *Parser) period(value interface{}, parse func(interface{}) (time.Time, error)) (period Period, err error) {
+	var start, end string
+	if start, end, err = splitPeriod(value); err != nil {
+		return Period{}, err
+	}
+	if period.Start, err = parse(start); err != nil {
+		return Period{}, err
+	}
+	if period.End, err = parse(end); err != nil {
+		return Period{}, err
+	}
+	return period, nil
+}
+
+// Parse a @sys.date-period value. (eg. "2018-01-01/2018-01-31")
+func (p *Parser) DatePeriod(value interface{}) (Period, error) {
+	return p.period(value, p.Date)
+}
+
+// Parse a @sys.time-period value. (eg. "13:00:00/14:00:00")
+func (p *Parser) TimePeriod(value interface{}) (Period, error) {
+	return p.period(value, p.Time)
+}
+
+// Parse a @sys.date-time-period value.
+func (p *Parser) DateTimePeriod(value interface{}) (Period, error) {
+	return p.period(value, p.DateTime)
+}
+
+// Get amount and unit (or currency) from an object value.
+func amountObject(value interface{}) (amount float64, unit, currency string, err error) {
+	var obj struct {
+		Amount   json.Number `json:"amount"`
+		Unit     string      `json:"unit"`
+		Currency string      `json:"currency"`
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v) // json string
+	default:
+		if data, err = json.Marshal(v); err != nil {
+			return 0, "", "", err
+		}
+	}
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return 0, "", "", fmt.Errorf("not an amount object: %s", err)
+	}
+	if amount, err = strconv.ParseFloat(string(obj.Amount), 64); err != nil {
+		return 0, "", "", fmt.Errorf("invalid amount: %s", obj.Amount)
+	}
+	return amount, obj.Unit, obj.Currency, nil
+}
+
+// Parse a @sys.duration value. (eg. {"amount": 10, "unit": "min"})
+func ParseDuration(value interface{}) (time.Duration, error) {
+	amount, unit, _, err := amountObject(value)
+	if err != nil {
+		return 0, err
+	}
+	d, exists := durationUnits[unit]
+	if !exists {
+		return 0, fmt.Errorf("unknown duration unit: %s", unit)
+	}
+	return time.Duration(amount * float64(d)), nil
+}
+
+// Parse a @sys.unit-currency value. (eg. {"amount": 10, "currency": "USD"})
+func ParseMoney(value interface{}) (Money, error) {
+	amount, unit, currency, err := amountObject(value)
+	if err != nil {
+		return Money{}, err
+	}
+	if len(currency) <= 0 {
+		currency = unit
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Parse a unit value. (eg. {"amount": 3, "unit": "km"})
+func ParseQuantity(value interface{}) (Quantity, error) {
+	amount, unit, _, err := amountObject(value)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Amount: amount, Unit: unit}, nil
+}
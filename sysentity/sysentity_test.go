@@ -0,0 +1,176 @@
+package sysentity_test
+
+import (
+	"testing"
+	"time"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/sysentity"
+)
+
+// Get a parser in a non-UTC timezone.
+func seoulParser(t *testing.T) *sysentity.Parser {
+	p, err := sysentity.ParserFor(df.QueryRequest{Timezone: "Asia/Seoul"})
+	if err != nil {
+		t.Fatalf("failed to create parser: %s", err)
+	}
+	return p
+}
+
+func TestNewParser(t *testing.T) {
+	if p, err := sysentity.NewParser(""); err != nil || p.Location != time.UTC {
+		t.Errorf("expected UTC parser, got %v (%v)", p, err)
+	}
+	if _, err := sysentity.NewParser("No/Such_Zone"); err == nil {
+		t.Errorf("expected an error for unknown timezone")
+	}
+
+	// nil parser falls back to UTC
+	var p *sysentity.Parser
+	if d, err := p.Date("2018-01-02"); err != nil || d.Location() != time.UTC {
+		t.Errorf("expected date in UTC, got %s (%v)", d, err)
+	}
+}
+
+func TestDateAndTime(t *testing.T) {
+	p := seoulParser(t)
+
+	if d, err := p.Date("2018-01-02"); err != nil {
+		t.Errorf("failed to parse date: %s", err)
+	} else if !d.Equal(time.Date(2018, 1, 2, 0, 0, 0, 0, p.Location)) || d.Location() != p.Location {
+		t.Errorf("unexpected date: %s", d)
+	}
+
+	if tm, err := p.Time("13:04:05"); err != nil {
+		t.Errorf("failed to parse time: %s", err)
+	} else if tm.Year() != 0 || tm.Hour() != 13 || tm.Minute() != 4 || tm.Second() != 5 || tm.Location() != p.Location {
+		t.Errorf("unexpected time: %s", tm)
+	}
+
+	// 2018-01-01T20:00:00Z is 2018-01-02 in Seoul
+	if tm, err := p.TimeOn("13:04:05", time.Date(2018, 1, 1, 20, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("failed to parse time on date: %s", err)
+	} else if !tm.Equal(time.Date(2018, 1, 2, 13, 4, 5, 0, p.Location)) {
+		t.Errorf("unexpected time on date: %s", tm)
+	}
+
+	for _, value := range []interface{}{"", "  ", nil, 20180102, "2018/01/02"} {
+		if _, err := p.Date(value); err == nil {
+			t.Errorf("expected an error for date %#v", value)
+		}
+	}
+	for _, value := range []interface{}{"", nil, "1:2", "25:00:00"} {
+		if _, err := p.Time(value); err == nil {
+			t.Errorf("expected an error for time %#v", value)
+		}
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	p := seoulParser(t)
+
+	for value, expected := range map[string]time.Time{
+		"2018-01-02T04:04:05Z":      time.Date(2018, 1, 2, 4, 4, 5, 0, time.UTC),
+		"2018-01-02T13:04:05+09:00": time.Date(2018, 1, 2, 4, 4, 5, 0, time.UTC),
+		"2018-01-01T23:04:05-05:00": time.Date(2018, 1, 2, 4, 4, 5, 0, time.UTC),
+		"2018-01-02T13:04:05":       time.Date(2018, 1, 2, 4, 4, 5, 0, time.UTC), // naive: in Seoul
+	} {
+		if tm, err := p.DateTime(value); err != nil {
+			t.Errorf("failed to parse date-time '%s': %s", value, err)
+		} else if !tm.Equal(expected) {
+			t.Errorf("date-time '%s': expected %s, got %s", value, expected, tm)
+		} else if tm.Location() != p.Location {
+			t.Errorf("date-time '%s': expected location %s, got %s", value, p.Location, tm.Location())
+		}
+	}
+
+	if _, err := p.DateTime("2018-01-02 13:04:05"); err == nil {
+		t.Errorf("expected an error for malformed date-time")
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	p := seoulParser(t)
+
+	if period, err := p.DatePeriod("2018-01-01/2018-01-31"); err != nil {
+		t.Errorf("failed to parse date period: %s", err)
+	} else if !period.Start.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, p.Location)) || !period.End.Equal(time.Date(2018, 1, 31, 0, 0, 0, 0, p.Location)) {
+		t.Errorf("unexpected date period: %+v", period)
+	}
+
+	if period, err := p.TimePeriod("13:00:00/14:30:00"); err != nil {
+		t.Errorf("failed to parse time period: %s", err)
+	} else if period.Start.Hour() != 13 || period.End.Hour() != 14 || period.End.Minute() != 30 {
+		t.Errorf("unexpected time period: %+v", period)
+	}
+
+	if period, err := p.DateTimePeriod("2018-01-02T13:00:00+09:00/2018-01-02T05:00:00Z"); err != nil {
+		t.Errorf("failed to parse date-time period: %s", err)
+	} else if !period.Start.Equal(time.Date(2018, 1, 2, 4, 0, 0, 0, time.UTC)) || !period.End.Equal(time.Date(2018, 1, 2, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date-time period: %+v", period)
+	}
+
+	for _, value := range []interface{}{"2018-01-01", "2018-01-01/2018-01-02/2018-01-03", "2018-01-01/xxx", nil} {
+		if _, err := p.DatePeriod(value); err == nil {
+			t.Errorf("expected an error for date period %#v", value)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"object":        map[string]interface{}{"amount": 10.0, "unit": "min"},
+		"json string":   `{"amount": 10, "unit": "min"}`,
+		"string amount": map[string]interface{}{"amount": "10", "unit": "min"},
+	} {
+		if d, err := sysentity.ParseDuration(value); err != nil || d != 10*time.Minute {
+			t.Errorf("%s: expected 10m, got %s (%v)", name, d, err)
+		}
+	}
+
+	if d, err := sysentity.ParseDuration(`{"amount": 1.5, "unit": "h"}`); err != nil || d != 90*time.Minute {
+		t.Errorf("expected 1h30m, got %s (%v)", d, err)
+	}
+
+	for _, value := range []interface{}{
+		`{"amount": 10, "unit": "fortnight"}`,
+		`{"amount": "ten", "unit": "min"}`,
+		`not json`,
+		map[string]interface{}{"unit": "min"},
+	} {
+		if _, err := sysentity.ParseDuration(value); err == nil {
+			t.Errorf("expected an error for duration %#v", value)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"object":      map[string]interface{}{"amount": 10.5, "currency": "USD"},
+		"json string": `{"amount": 10.5, "currency": "USD"}`,
+		"unit":        `{"amount": 10.5, "unit": "USD"}`,
+	} {
+		if m, err := sysentity.ParseMoney(value); err != nil || m != (sysentity.Money{Amount: 10.5, Currency: "USD"}) {
+			t.Errorf("%s: unexpected money: %+v (%v)", name, m, err)
+		}
+	}
+
+	if _, err := sysentity.ParseMoney(`{"currency": "USD"}`); err == nil {
+		t.Errorf("expected an error for money without amount")
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"object":      map[string]interface{}{"amount": 3, "unit": "km"},
+		"json string": `{"amount": 3, "unit": "km"}`,
+	} {
+		if q, err := sysentity.ParseQuantity(value); err != nil || q != (sysentity.Quantity{Amount: 3, Unit: "km"}) {
+			t.Errorf("%s: unexpected quantity: %+v (%v)", name, q, err)
+		}
+	}
+
+	if _, err := sysentity.ParseQuantity([]int{1, 2}); err == nil {
+		t.Errorf("expected an error for non-object quantity")
+	}
+}
@@ -0,0 +1,180 @@
+package dialogflow
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// An entry of conversation transcripts.
+type TranscriptEntry struct {
+	Time       time.Time      `json:"time"`
+	DurationMs int64          `json:"durationMs"`
+	SessionId  string         `json:"sessionId"`
+	IntentId   string         `json:"intentId,omitempty"`
+	IntentName string         `json:"intentName,omitempty"`
+	Action     string         `json:"action,omitempty"`
+	Request    QueryRequest   `json:"request"`
+	Response   *QueryResponse `json:"response,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// Recorder which appends transcript entries to a JSON Lines sink.
+//
+// (safe for concurrent use)
+type TranscriptRecorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// Get a new transcript recorder which writes to given writer.
+func NewTranscriptRecorder(w io.Writer) *TranscriptRecorder {
+	return &TranscriptRecorder{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Option for recording transcripts of all queries.
+func WithTranscriptRecorder(recorder *TranscriptRecorder) ClientOption {
+	return func(c *Client) {
+		c.recorder = recorder
+	}
+}
+
+// Append an entry as a line.
+func (r *TranscriptRecorder) Record(entry TranscriptEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.encoder.Encode(entry)
+}
+
+// Record a query and its result. (called with defer)
+func (c *Client) record(query QueryRequest, started time.Time, result *QueryResponse, err *error) {
+	if c.recorder == nil {
+		return
+	}
+
+	entry := TranscriptEntry{
+		Time:       started,
+		DurationMs: time.Since(started).Milliseconds(),
+		SessionId:  query.SessionId,
+		Request:    query,
+	}
+	if *err != nil {
+		entry.Error = (*err).Error()
+	} else {
+		response := *result
+		entry.Response = &response
+		entry.IntentId = response.Result.Metadata.IntentId
+		entry.IntentName = response.Result.Metadata.IntentName
+		entry.Action = response.Result.Action
+	}
+
+	if e := c.recorder.Record(entry); e != nil {
+		if l := c.log(); l != nil {
+			l.Error("failed to record transcript", "sessionId", query.SessionId, "error", e)
+		}
+	}
+}
+
+// Read transcript entries from JSON Lines.
+func ReadTranscript(r io.Reader) (entries []TranscriptEntry, err error) {
+	entries = []TranscriptEntry{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) <= 0 {
+			continue
+		}
+
+		var entry TranscriptEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read transcript at line %d: %s", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Result of replaying a transcript entry.
+type ReplayResult struct {
+	Entry    TranscriptEntry
+	Response QueryResponse
+	Err      error
+	Changes  []string // differences from the recorded response, empty if nothing changed
+}
+
+// Check if the replayed response differs from the recorded one.
+func (r ReplayResult) Changed() bool {
+	return len(r.Changes) > 0 || r.Err != nil
+}
+
+// Replay transcript entries as text queries, and compare intents, actions, parameters, and speeches.
+//
+// Queries are sent in sessions prefixed with given prefix (eg. "replay-"),
+// so that replays do not affect the recorded sessions, and they are not recorded to the client's transcript.
+// Entries without responses or query texts (eg. voice queries) are skipped.
+func (c *Client) ReplayTranscript(ctx context.Context, entries []TranscriptEntry, sessionPrefix string) (results []ReplayResult, err error) {
+	results = []ReplayResult{}
+
+	for _, entry := range entries {
+		if entry.Response == nil || len(entry.Request.Query) <= 0 {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return results, err
+		}
+
+		query := entry.Request
+		query.SessionId = sessionPrefix + query.SessionId
+
+		result := ReplayResult{Entry: entry}
+		if result.Response, result.Err = c.queryText(ctx, query); result.Err == nil {
+			result.Changes = compareResponses(*entry.Response, result.Response)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Compare two query responses, returning descriptions of differences.
+func compareResponses(recorded, replayed QueryResponse) (changes []string) {
+	changes = []string{}
+
+	if a, b := recorded.Result.Metadata.IntentName, replayed.Result.Metadata.IntentName; a != b {
+		changes = append(changes, fmt.Sprintf("intent: '%s' => '%s'", a, b))
+	}
+	if a, b := recorded.Result.Action, replayed.Result.Action; a != b {
+		changes = append(changes, fmt.Sprintf("action: '%s' => '%s'", a, b))
+	}
+	if a, b := recorded.Result.ActionIncomplete, replayed.Result.ActionIncomplete; a != b {
+		changes = append(changes, fmt.Sprintf("action incomplete: %t => %t", a, b))
+	}
+	if a, b := normalizeJson(recorded.Result.Parameters), normalizeJson(replayed.Result.Parameters); !reflect.DeepEqual(a, b) {
+		changes = append(changes, fmt.Sprintf("parameters: %v => %v", a, b))
+	}
+	if a, b := recorded.Result.Fulfillment.Speech, replayed.Result.Fulfillment.Speech; a != b {
+		changes = append(changes, fmt.Sprintf("speech: '%s' => '%s'", a, b))
+	}
+
+	return changes
+}
+
+// Normalize a value through json, for comparison.
+func normalizeJson(value interface{}) (normalized interface{}) {
+	if data, err := json.Marshal(value); err == nil {
+		if err = json.Unmarshal(data, &normalized); err == nil {
+			return normalized
+		}
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package dialogflow_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/dialogflowtest"
+)
+
+func TestReplayTranscript(t *testing.T) {
+	s := dialogflowtest.NewServer()
+	defer s.Close()
+
+	action := "order"
+	s.SetQueryHandler(func(query df.QueryRequest) (response df.QueryResponse) {
+		response.Result.Action = action
+		return response
+	})
+
+	var transcript bytes.Buffer
+	client := s.NewClient("token", df.WithTranscriptRecorder(df.NewTranscriptRecorder(&transcript)))
+
+	if _, err := client.QueryText(df.QueryRequest{Query: []string{"pizza"}, SessionId: "session", Language: df.English}); err != nil {
+		t.Fatalf("query failed: %s", err)
+	}
+	if _, err := client.QueryVoice(df.QueryRequest{SessionId: "session", Language: df.English}, bytes.NewReader(validWav())); err != nil {
+		t.Fatalf("voice query failed: %s", err)
+	}
+
+	entries, err := df.ReadTranscript(bytes.NewReader(transcript.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to read transcript: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	recorded := transcript.Len()
+
+	action = "changed"
+	results, err := client.ReplayTranscript(context.Background(), entries, "replay-")
+	if err != nil {
+		t.Fatalf("replay failed: %s", err)
+	}
+
+	// voice query is skipped
+	if len(results) != 1 {
+		t.Fatalf("expected 1 replayed entry, got %d", len(results))
+	}
+	if !results[0].Changed() || len(results[0].Changes) != 1 {
+		t.Errorf("expected a change of action, got %v", results[0].Changes)
+	}
+
+	// replays are not recorded
+	if transcript.Len() != recorded {
+		t.Errorf("expected replays not to be recorded, transcript grew from %d to %d bytes", recorded, transcript.Len())
+	}
+}
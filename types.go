@@ -95,12 +95,12 @@ type QueryResponse struct {
 	Timestamp string      `json:"timestamp"`
 	Language  LanguageTag `json:"lang"`
 	Result    struct {
-		Source           string                 `json:"source"`
-		ResolvedQuery    string                 `json:"resolvedQuery"`
-		Action           string                 `json:"action"`
-		ActionIncomplete bool                   `json:"actionIncomplete"`
-		Parameters       map[string]interface{} `json:"parameters"`
-		Contexts         []ContextObject        `json:"contexts"`
+		Source           string     `json:"source"`
+		ResolvedQuery    string     `json:"resolvedQuery"`
+		Action           string     `json:"action"`
+		ActionIncomplete bool       `json:"actionIncomplete"`
+		Parameters       Parameters `json:"parameters"`
+		Contexts         Contexts   `json:"contexts"`
 		Fulfillment      struct {
 			Speech   string    `json:"speech"`
 			Messages []Message `json:"messages"` // https://dialogflow.com/docs/reference/agent/query#message_objects
@@ -112,293 +112,6 @@ type QueryResponse struct {
 	SessionId string       `json:"sessionId"`
 }
 
-// https://dialogflow.com/docs/reference/agent/query#message_objects
-type MessageType int
-
-const (
-	TextResponseMessageObjectType  MessageType = 0
-	CardMessageObjectType          MessageType = 1
-	QuickRepliesMessageObjectType  MessageType = 2
-	ImageMessageObjectType         MessageType = 3
-	CustomPayloadMessageObjectType MessageType = 4
-	NoSuchObjectType               MessageType = -1
-)
-
-type MessageObject struct {
-	Type     MessageType `json:"type"`
-	Platform string      `json:"platform,omitempty"`
-}
-
-type Message map[string]interface{}
-
-func (f Message) Type() MessageType {
-	if v, exists := map[string]interface{}(f)["type"]; exists {
-		if v, ok := v.(MessageType); ok {
-			return v
-		}
-	}
-	return NoSuchObjectType
-}
-
-type TextResponseMessageObject struct {
-	MessageObject
-	Speech []string `json:"speech"`
-}
-
-// helper function for creating a TextResponseMessage
-func TextResponseMessage(platform string, speech []string) Message {
-	msg := map[string]interface{}{
-		"type":   TextResponseMessageObjectType,
-		"speech": speech,
-	}
-	if len(platform) > 0 {
-		msg["platform"] = platform
-	}
-
-	return Message(msg)
-}
-
-type CardMessageObject struct {
-	MessageObject
-	Title    string              `json:"title"`
-	Subtitle string              `json:"subtitle"`
-	Buttons  []CardMessageButton `json:"buttons"`
-}
-
-type CardMessageButton struct {
-	Text     string `json:"text"`
-	Postback string `json:"postback"`
-}
-
-type QuickRepliesMessageObject struct {
-	MessageObject
-	Title   string   `json:"title"`
-	Replies []string `json:"replies"`
-}
-
-type ImageMessageObject struct {
-	MessageObject
-	ImageUrl string `json:"imageUrl"`
-}
-
-type CustomPayloadMessageObject struct {
-	MessageObject
-	Payload interface{} `json:"payload"`
-}
-
-func (f Message) ToTextResponseMessage() TextResponseMessageObject {
-	m := map[string]interface{}(f)
-
-	var typ MessageType = -1
-	var platform string = ""
-	var speech []string = nil
-
-	if v, exists := m["type"]; exists {
-		switch v.(type) {
-		case MessageType:
-			typ = v.(MessageType)
-		}
-	}
-	if v, exists := m["platform"]; exists {
-		switch v.(type) {
-		case string:
-			platform = v.(string)
-		}
-	}
-	if v, exists := m["speech"]; exists {
-		switch v.(type) {
-		case []string:
-			speech = v.([]string)
-		case string:
-			speech = []string{v.(string)}
-		}
-	}
-
-	return TextResponseMessageObject{
-		MessageObject: MessageObject{
-			Type:     typ,
-			Platform: platform,
-		},
-		Speech: speech,
-	}
-}
-
-func (f Message) ToCardMessage() CardMessageObject {
-	m := map[string]interface{}(f)
-
-	var typ MessageType = -1
-	var platform string = ""
-	var title string = ""
-	var subtitle string = ""
-	var buttons []CardMessageButton = []CardMessageButton{}
-
-	if v, exists := m["type"]; exists {
-		switch v.(type) {
-		case MessageType:
-			typ = v.(MessageType)
-		}
-	}
-	if v, exists := m["platform"]; exists {
-		switch v.(type) {
-		case string:
-			platform = v.(string)
-		}
-	}
-	if v, exists := m["title"]; exists {
-		switch v.(type) {
-		case string:
-			title = v.(string)
-		}
-	}
-	if v, exists := m["subtitle"]; exists {
-		switch v.(type) {
-		case string:
-			subtitle = v.(string)
-		}
-	}
-	if v, exists := m["buttons"]; exists {
-		switch v.(type) {
-		case []map[string]string:
-			for _, m := range v.([]map[string]string) {
-				text := ""
-				postback := ""
-				if v, exists := m["text"]; exists {
-					text = v
-				}
-				if v, exists := m["postback"]; exists {
-					postback = v
-				}
-				buttons = append(buttons, CardMessageButton{
-					Text:     text,
-					Postback: postback,
-				})
-			}
-		}
-	}
-
-	return CardMessageObject{
-		MessageObject: MessageObject{
-			Type:     typ,
-			Platform: platform,
-		},
-		Title:    title,
-		Subtitle: subtitle,
-		Buttons:  buttons,
-	}
-}
-
-func (f Message) ToQuickRepliesMessage() QuickRepliesMessageObject {
-	m := map[string]interface{}(f)
-
-	var typ MessageType = -1
-	var platform string = ""
-	var title string = ""
-	var replies []string = []string{}
-
-	if v, exists := m["type"]; exists {
-		switch v.(type) {
-		case MessageType:
-			typ = v.(MessageType)
-		}
-	}
-	if v, exists := m["platform"]; exists {
-		switch v.(type) {
-		case string:
-			platform = v.(string)
-		}
-	}
-	if v, exists := m["title"]; exists {
-		switch v.(type) {
-		case string:
-			title = v.(string)
-		}
-	}
-	if v, exists := m["replies"]; exists {
-		switch v.(type) {
-		case []string:
-			for _, s := range v.([]string) {
-				replies = append(replies, s)
-			}
-		}
-	}
-
-	return QuickRepliesMessageObject{
-		MessageObject: MessageObject{
-			Type:     typ,
-			Platform: platform,
-		},
-		Title:   title,
-		Replies: replies,
-	}
-}
-
-func (f Message) ToImageMessage() ImageMessageObject {
-	m := map[string]interface{}(f)
-
-	var typ MessageType = -1
-	var platform string = ""
-	var imageUrl string = ""
-
-	if v, exists := m["type"]; exists {
-		switch v.(type) {
-		case MessageType:
-			typ = v.(MessageType)
-		}
-	}
-	if v, exists := m["platform"]; exists {
-		switch v.(type) {
-		case string:
-			platform = v.(string)
-		}
-	}
-	if v, exists := m["imageUrl"]; exists {
-		switch v.(type) {
-		case string:
-			imageUrl = v.(string)
-		}
-	}
-
-	return ImageMessageObject{
-		MessageObject: MessageObject{
-			Type:     typ,
-			Platform: platform,
-		},
-		ImageUrl: imageUrl,
-	}
-}
-
-func (f Message) ToCustomPayloadMessage() CustomPayloadMessageObject {
-	m := map[string]interface{}(f)
-
-	var typ MessageType = -1
-	var platform string = ""
-	var payload interface{} = nil
-
-	if v, exists := m["type"]; exists {
-		switch v.(type) {
-		case MessageType:
-			typ = v.(MessageType)
-		}
-	}
-	if v, exists := m["platform"]; exists {
-		switch v.(type) {
-		case string:
-			platform = v.(string)
-		}
-	}
-	if v, exists := m["payload"]; exists {
-		payload = v
-	}
-
-	return CustomPayloadMessageObject{
-		MessageObject: MessageObject{
-			Type:     typ,
-			Platform: platform,
-		},
-		Payload: payload,
-	}
-}
-
 type Metadata struct {
 	IntentId                  string `json:"intentId"`
 	WebhookUsed               string `json:"webhookUsed"`
@@ -535,9 +248,9 @@ type CortanaCommand struct {
 //
 // https://dialogflow.com/docs/reference/agent/contexts#context_object
 type ContextObject struct {
-	Name       string                 `json:"name,omitempty"`
-	Lifespan   int                    `json:"lifespan,omitempty"`
-	Parameters map[string]interface{} `json:"parameters,omitempty"` // XXX - document's specification and its sample request is different...?
+	Name       string     `json:"name,omitempty"`
+	Lifespan   int        `json:"lifespan,omitempty"`
+	Parameters Parameters `json:"parameters,omitempty"` // XXX - document's specification and its sample request is different...?
 }
 
 type ContextResponseCreated struct {
@@ -3,14 +3,20 @@ package dialogflow
 // https://dialogflow.com/docs/reference/agent/userentities
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
 
 // Create new user entities.
 func (c *Client) CreateUserEntities(sessionId string, entities []UserEntityObject) (result ApiResponse, err error) {
+	return c.CreateUserEntitiesContext(context.Background(), sessionId, entities)
+}
+
+// Create new user entities with given context.
+func (c *Client) CreateUserEntitiesContext(ctx context.Context, sessionId string, entities []UserEntityObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPost("userEntities", nil, nil, NewUserEntitiesObject{
+	if bytes, err = c.httpPost(ctx, "userEntities", nil, nil, NewUserEntitiesObject{
 		SessionId: sessionId,
 		Entities:  entities,
 	}); err == nil {
@@ -24,8 +30,13 @@ func (c *Client) CreateUserEntities(sessionId string, entities []UserEntityObjec
 
 // Update user entity.
 func (c *Client) UpdateUserEntity(name string, entity UserEntityObject) (result ApiResponse, err error) {
+	return c.UpdateUserEntityContext(context.Background(), name, entity)
+}
+
+// Update user entity with given context.
+func (c *Client) UpdateUserEntityContext(ctx context.Context, name string, entity UserEntityObject) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpPut(fmt.Sprintf("userEntities/%s", name), nil, entity); err == nil {
+	if bytes, err = c.httpPut(ctx, fmt.Sprintf("userEntities/%s", name), nil, entity); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -36,8 +47,13 @@ func (c *Client) UpdateUserEntity(name string, entity UserEntityObject) (result
 
 // Get a user entity.
 func (c *Client) UserEntity(name string) (result UserEntityObject, err error) {
+	return c.UserEntityContext(context.Background(), name)
+}
+
+// Get a user entity with given context.
+func (c *Client) UserEntityContext(ctx context.Context, name string) (result UserEntityObject, err error) {
 	var bytes []byte
-	if bytes, err = c.httpGet(fmt.Sprintf("userEntities/%s", name), nil, nil); err == nil {
+	if bytes, err = c.httpGet(ctx, fmt.Sprintf("userEntities/%s", name), nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
@@ -48,8 +64,13 @@ func (c *Client) UserEntity(name string) (result UserEntityObject, err error) {
 
 // Delete user entity.
 func (c *Client) DeleteUserEntity(name string) (result ApiResponse, err error) {
+	return c.DeleteUserEntityContext(context.Background(), name)
+}
+
+// Delete user entity with given context.
+func (c *Client) DeleteUserEntityContext(ctx context.Context, name string) (result ApiResponse, err error) {
 	var bytes []byte
-	if bytes, err = c.httpDelete(fmt.Sprintf("userEntities/%s", name), nil, nil, nil); err == nil {
+	if bytes, err = c.httpDelete(ctx, fmt.Sprintf("userEntities/%s", name), nil, nil, nil); err == nil {
 		if err = json.Unmarshal(bytes, &result); err == nil {
 			return result, nil
 		}
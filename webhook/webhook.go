@@ -0,0 +1,88 @@
+// Package webhook provides models and an http.Handler for Dialogflow fulfillment webhooks.
+package webhook
+
+// https://dialogflow.com/docs/fulfillment#request
+// https://dialogflow.com/docs/fulfillment#response
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	df "github.com/meinside/dialogflow-go"
+)
+
+// Webhook request from Dialogflow.
+type Request struct {
+	df.QueryResponse
+
+	OriginalRequest OriginalRequest `json:"originalRequest"`
+}
+
+// Original request from the integrated platform.
+type OriginalRequest struct {
+	Source  string      `json:"source"`
+	Version string      `json:"version,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// Webhook response to Dialogflow.
+type Response struct {
+	Speech        string                 `json:"speech,omitempty"`
+	DisplayText   string                 `json:"displayText,omitempty"`
+	Messages      []df.Message           `json:"messages,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+	ContextOut    []df.ContextObject     `json:"contextOut,omitempty"`
+	FollowupEvent *FollowupEvent         `json:"followupEvent,omitempty"`
+	Source        string                 `json:"source,omitempty"`
+}
+
+// Event which triggers an intent.
+type FollowupEvent struct {
+	Name string                 `json:"name"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// Function which handles a webhook request.
+type HandlerFunc func(ctx context.Context, request Request) (Response, error)
+
+// Get a new http.Handler which decodes webhook requests, calls given function, and encodes its responses.
+//
+// (when the function returns an error, it responds with http status 500)
+func NewHandler(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, fmt.Sprintf("method not allowed: %s", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var bytes []byte
+		var err error
+		if bytes, err = ioutil.ReadAll(r.Body); err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		var request Request
+		if err = json.Unmarshal(bytes, &request); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		var response Response
+		if response, err = fn(r.Context(), request); err != nil {
+			http.Error(w, fmt.Sprintf("failed to handle request: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		if bytes, err = json.Marshal(response); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode response: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		w.Write(bytes)
+	})
+}
@@ -0,0 +1,111 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	df "github.com/meinside/dialogflow-go"
+	"github.com/meinside/dialogflow-go/webhook"
+)
+
+// Webhook request, as sent by Dialogflow.
+const requestJson = `{
+  "id": "a1b2c3",
+  "timestamp": "2017-11-01T00:00:00.000Z",
+  "lang": "en",
+  "result": {
+    "source": "agent",
+    "resolvedQuery": "I want a large pizza",
+    "action": "order",
+    "parameters": {"size": "large"},
+    "contexts": [{"name": "ordering", "lifespan": 2, "parameters": {"size": "large"}}],
+    "metadata": {"intentId": "i1", "intentName": "order"},
+    "fulfillment": {"speech": "", "messages": [{"type": 0, "speech": ""}]},
+    "score": 1
+  },
+  "status": {"code": 200, "errorType": "success"},
+  "sessionId": "session",
+  "originalRequest": {"source": "google", "version": "2", "data": {"user": {"locale": "en-US"}}}
+}`
+
+// Send a request to given handler.
+func serve(handler http.Handler, method, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, "/webhook", strings.NewReader(body)))
+	return recorder
+}
+
+func TestHandler(t *testing.T) {
+	var received webhook.Request
+	handler := webhook.NewHandler(func(ctx context.Context, request webhook.Request) (webhook.Response, error) {
+		received = request
+
+		size, _ := request.Result.Parameters.String("size")
+		return webhook.Response{
+			Speech:     "One " + size + " pizza.",
+			Messages:   []df.Message{df.TextResponseMessage("", []string{"One " + size + " pizza."})},
+			ContextOut: []df.ContextObject{{Name: "ordered", Lifespan: 1}},
+			Source:     "test",
+		}, nil
+	})
+
+	recorder := serve(handler, "POST", requestJson)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected http status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("expected json content type, got '%s'", recorder.Header().Get("Content-Type"))
+	}
+
+	// decoded request
+	if received.Result.Action != "order" || received.SessionId != "session" || received.Result.Metadata.IntentName != "order" {
+		t.Errorf("unexpected request: %+v", received.QueryResponse)
+	}
+	if !received.Result.Contexts.IsActive("ordering") || received.OriginalRequest.Source != "google" {
+		t.Errorf("unexpected contexts or original request: %+v, %+v", received.Result.Contexts, received.OriginalRequest)
+	}
+
+	// encoded response
+	var response map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+	if response["speech"] != "One large pizza." || response["source"] != "test" {
+		t.Errorf("unexpected response: %s", recorder.Body.String())
+	}
+	if messages, ok := response["messages"].([]interface{}); !ok || len(messages) != 1 || messages[0].(map[string]interface{})["type"] != 0.0 {
+		t.Errorf("unexpected messages: %s", recorder.Body.String())
+	}
+	if _, exists := response["followupEvent"]; exists {
+		t.Errorf("expected no followupEvent, got %s", recorder.Body.String())
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	called := false
+	handler := webhook.NewHandler(func(ctx context.Context, request webhook.Request) (webhook.Response, error) {
+		called = true
+		if request.Result.Action == "fail" {
+			return webhook.Response{}, errors.New("failed")
+		}
+		return webhook.Response{}, nil
+	})
+
+	if recorder := serve(handler, "GET", ""); recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected http status 405, got %d", recorder.Code)
+	}
+	if recorder := serve(handler, "POST", "{not json"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected http status 400, got %d", recorder.Code)
+	}
+	if called {
+		t.Errorf("expected the handler not to be called for invalid requests")
+	}
+	if recorder := serve(handler, "POST", `{"result":{"action":"fail"}}`); recorder.Code != http.StatusInternalServerError {
+		t.Errorf("expected http status 500, got %d", recorder.Code)
+	}
+}